@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrMiss = errors.New("cache: key not found")
+
+type Cache interface {
+	// Get returns ErrMiss when the key is absent or expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key. A ttl of zero keeps the entry until it is
+	// evicted or deleted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	DeleteByPrefix(ctx context.Context, prefix string) error
+}
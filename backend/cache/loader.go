@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const refreshTimeout = 10 * time.Second
+
+type Outcome string
+
+const (
+	Hit       Outcome = "hit"
+	StaleHit  Outcome = "stale"
+	Miss      Outcome = "miss"
+	Coalesced Outcome = "coalesced"
+)
+
+type LoadOptions struct {
+	// TTL is how long a loaded value is served as fresh.
+	TTL time.Duration
+	// StaleTTL extends the lifetime of an entry past TTL. During that window
+	// the expired value is returned immediately and refreshed in the
+	// background. Zero disables stale-while-revalidate.
+	StaleTTL time.Duration
+}
+
+type LoadFunc func(ctx context.Context) ([]byte, error)
+
+// Loader coalesces concurrent misses for the same key into a single call of
+// the load function and optionally serves stale entries while they are being
+// refreshed.
+type Loader struct {
+	cache Cache
+	group singleflight.Group
+}
+
+func NewLoader(c Cache) *Loader {
+	return &Loader{cache: c}
+}
+
+func (l *Loader) Fetch(ctx context.Context, key string, opts LoadOptions, load LoadFunc) ([]byte, Outcome, error) {
+	raw, err := l.cache.Get(ctx, key)
+	if err != nil && err != ErrMiss {
+		log.Printf("Cache GET error for key %s: %v", key, err)
+	}
+	if err == nil {
+		if value, freshUntil, ok := decodeEntry(raw); ok {
+			if time.Now().Before(freshUntil) {
+				return value, Hit, nil
+			}
+			if opts.StaleTTL > 0 {
+				l.refresh(key, opts, load)
+				return value, StaleHit, nil
+			}
+		}
+	}
+
+	value, err, shared := l.group.Do(key, func() (interface{}, error) {
+		return l.loadAndStore(context.WithoutCancel(ctx), key, opts, load)
+	})
+	if err != nil {
+		return nil, Miss, err
+	}
+	if shared {
+		return value.([]byte), Coalesced, nil
+	}
+	return value.([]byte), Miss, nil
+}
+
+func (l *Loader) refresh(key string, opts LoadOptions, load LoadFunc) {
+	l.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		value, err := l.loadAndStore(ctx, key, opts, load)
+		if err != nil {
+			log.Printf("Background refresh failed for cache key %s: %v", key, err)
+		}
+		return value, err
+	})
+}
+
+func (l *Loader) loadAndStore(ctx context.Context, key string, opts LoadOptions, load LoadFunc) ([]byte, error) {
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	freshUntil := time.Now().Add(opts.TTL)
+	if err := l.cache.Set(ctx, key, encodeEntry(value, freshUntil), opts.TTL+opts.StaleTTL); err != nil {
+		log.Printf("Failed to cache value for key %s: %v", key, err)
+	}
+	return value, nil
+}
+
+// Entries are stored as an 8 byte big-endian freshness deadline (unix nanos)
+// followed by the payload.
+func encodeEntry(value []byte, freshUntil time.Time) []byte {
+	entry := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(entry, uint64(freshUntil.UnixNano()))
+	copy(entry[8:], value)
+	return entry
+}
+
+func decodeEntry(entry []byte) ([]byte, time.Time, bool) {
+	if len(entry) < 8 {
+		return nil, time.Time{}, false
+	}
+	freshUntil := time.Unix(0, int64(binary.BigEndian.Uint64(entry)))
+	return entry[8:], freshUntil, true
+}
@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEntryEncoding(t *testing.T) {
+	freshUntil := time.Unix(1700000000, 123456789)
+
+	tests := []struct {
+		name   string
+		value  []byte
+		raw    []byte
+		wantOK bool
+	}{
+		{name: "payload", value: []byte(`{"a":1}`), wantOK: true},
+		{name: "empty payload", value: []byte{}, wantOK: true},
+		{name: "short entry", raw: []byte{1, 2, 3}, wantOK: false},
+		{name: "nil entry", raw: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := tt.raw
+			if tt.value != nil {
+				raw = encodeEntry(tt.value, freshUntil)
+			}
+			value, gotFresh, ok := decodeEntry(raw)
+			if ok != tt.wantOK {
+				t.Fatalf("decodeEntry ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !bytes.Equal(value, tt.value) {
+				t.Errorf("decodeEntry value = %q, want %q", value, tt.value)
+			}
+			if !gotFresh.Equal(freshUntil) {
+				t.Errorf("decodeEntry freshUntil = %v, want %v", gotFresh, freshUntil)
+			}
+		})
+	}
+}
+
+func TestLoaderFetch(t *testing.T) {
+	ctx := context.Background()
+	loadErr := errors.New("load failed")
+
+	tests := []struct {
+		name        string
+		seed        []byte
+		opts        LoadOptions
+		load        LoadFunc
+		want        string
+		wantOutcome Outcome
+		wantErr     error
+		wantLoads   int32
+	}{
+		{
+			name:        "miss loads and stores",
+			opts:        LoadOptions{TTL: time.Minute},
+			want:        "loaded",
+			wantOutcome: Miss,
+			wantLoads:   1,
+		},
+		{
+			name:        "fresh entry is a hit",
+			seed:        encodeEntry([]byte("cached"), time.Now().Add(time.Minute)),
+			opts:        LoadOptions{TTL: time.Minute},
+			want:        "cached",
+			wantOutcome: Hit,
+		},
+		{
+			name:        "expired entry without stale window reloads",
+			seed:        encodeEntry([]byte("cached"), time.Now().Add(-time.Second)),
+			opts:        LoadOptions{TTL: time.Minute},
+			want:        "loaded",
+			wantOutcome: Miss,
+			wantLoads:   1,
+		},
+		{
+			name:        "expired entry inside stale window is served",
+			seed:        encodeEntry([]byte("cached"), time.Now().Add(-time.Second)),
+			opts:        LoadOptions{TTL: time.Minute, StaleTTL: time.Minute},
+			want:        "cached",
+			wantOutcome: StaleHit,
+			wantLoads:   1,
+		},
+		{
+			name:        "undecodable entry reloads",
+			seed:        []byte("bad"),
+			opts:        LoadOptions{TTL: time.Minute},
+			want:        "loaded",
+			wantOutcome: Miss,
+			wantLoads:   1,
+		},
+		{
+			name:        "load error",
+			opts:        LoadOptions{TTL: time.Minute},
+			load:        func(ctx context.Context) ([]byte, error) { return nil, loadErr },
+			wantOutcome: Miss,
+			wantErr:     loadErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLRUCache(10)
+			if tt.seed != nil {
+				c.Set(ctx, "key", tt.seed, 0)
+			}
+
+			var loads int32
+			refreshed := make(chan struct{}, 1)
+			load := tt.load
+			if load == nil {
+				load = func(ctx context.Context) ([]byte, error) {
+					atomic.AddInt32(&loads, 1)
+					refreshed <- struct{}{}
+					return []byte("loaded"), nil
+				}
+			}
+
+			value, outcome, err := NewLoader(c).Fetch(ctx, "key", tt.opts, load)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fetch error = %v, want %v", err, tt.wantErr)
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("Fetch outcome = %s, want %s", outcome, tt.wantOutcome)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if string(value) != tt.want {
+				t.Errorf("Fetch value = %q, want %q", value, tt.want)
+			}
+
+			if tt.wantLoads > 0 {
+				select {
+				case <-refreshed:
+				case <-time.After(time.Second):
+					t.Fatal("load was not called")
+				}
+			}
+			if got := atomic.LoadInt32(&loads); got != tt.wantLoads {
+				t.Errorf("load called %d times, want %d", got, tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestLoaderStaleRefreshStoresNewValue(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(10)
+	c.Set(ctx, "key", encodeEntry([]byte("old"), time.Now().Add(-time.Second)), 0)
+
+	done := make(chan struct{})
+	loader := NewLoader(c)
+	opts := LoadOptions{TTL: time.Minute, StaleTTL: time.Minute}
+	_, outcome, err := loader.Fetch(ctx, "key", opts, func(ctx context.Context) ([]byte, error) {
+		defer close(done)
+		return []byte("new"), nil
+	})
+	if err != nil || outcome != StaleHit {
+		t.Fatalf("Fetch = %s, %v; want stale hit", outcome, err)
+	}
+	<-done
+
+	// The refresh stores the value after load returns, so poll briefly.
+	deadline := time.Now().Add(time.Second)
+	for {
+		value, outcome, err := loader.Fetch(ctx, "key", opts, func(ctx context.Context) ([]byte, error) {
+			return nil, errors.New("unexpected load")
+		})
+		if err == nil && outcome == Hit && string(value) == "new" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Fetch after refresh = %q, %s, %v; want \"new\" hit", value, outcome, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoaderCoalescesConcurrentMisses(t *testing.T) {
+	const callers = 8
+	ctx := context.Background()
+	loader := NewLoader(NewLRUCache(10))
+
+	var loads int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) ([]byte, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return []byte("loaded"), nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		outcomes = map[Outcome]int{}
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, outcome, err := loader.Fetch(ctx, "key", LoadOptions{TTL: time.Minute}, load)
+			if err != nil || string(value) != "loaded" {
+				t.Errorf("Fetch = %q, %v", value, err)
+			}
+			mu.Lock()
+			outcomes[outcome]++
+			mu.Unlock()
+		}()
+	}
+
+	// Give every caller time to join the in-flight load before releasing it.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&loads); got != 1 {
+		t.Errorf("load called %d times, want 1", got)
+	}
+	// singleflight reports the call as shared to the caller that ran it too.
+	if outcomes[Miss]+outcomes[Coalesced] != callers || outcomes[Coalesced] < callers-1 {
+		t.Errorf("outcomes = %v, want at least %d coalesced", outcomes, callers-1)
+	}
+}
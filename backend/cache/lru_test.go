@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		max     int
+		run     func(c *LRUCache)
+		present []string
+		missing []string
+	}{
+		{
+			name: "get returns stored value",
+			max:  2,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "a", []byte("1"), 0)
+			},
+			present: []string{"a"},
+			missing: []string{"b"},
+		},
+		{
+			name: "evicts least recently set",
+			max:  2,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "a", []byte("1"), 0)
+				c.Set(ctx, "b", []byte("2"), 0)
+				c.Set(ctx, "c", []byte("3"), 0)
+			},
+			present: []string{"b", "c"},
+			missing: []string{"a"},
+		},
+		{
+			name: "get refreshes recency",
+			max:  2,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "a", []byte("1"), 0)
+				c.Set(ctx, "b", []byte("2"), 0)
+				c.Get(ctx, "a")
+				c.Set(ctx, "c", []byte("3"), 0)
+			},
+			present: []string{"a", "c"},
+			missing: []string{"b"},
+		},
+		{
+			name: "overwrite does not grow",
+			max:  2,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "a", []byte("1"), 0)
+				c.Set(ctx, "b", []byte("2"), 0)
+				c.Set(ctx, "a", []byte("3"), 0)
+			},
+			present: []string{"a", "b"},
+		},
+		{
+			name: "zero max is unbounded",
+			max:  0,
+			run: func(c *LRUCache) {
+				for _, key := range []string{"a", "b", "c", "d"} {
+					c.Set(ctx, key, []byte(key), 0)
+				}
+			},
+			present: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "expired entries miss",
+			max:  2,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "a", []byte("1"), time.Nanosecond)
+				c.Set(ctx, "b", []byte("2"), time.Hour)
+				time.Sleep(time.Millisecond)
+			},
+			present: []string{"b"},
+			missing: []string{"a"},
+		},
+		{
+			name: "delete",
+			max:  3,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "a", []byte("1"), 0)
+				c.Set(ctx, "b", []byte("2"), 0)
+				c.Delete(ctx, "a", "missing")
+			},
+			present: []string{"b"},
+			missing: []string{"a"},
+		},
+		{
+			name: "delete by prefix",
+			max:  3,
+			run: func(c *LRUCache) {
+				c.Set(ctx, "property:list:1", []byte("1"), 0)
+				c.Set(ctx, "property:list:2", []byte("2"), 0)
+				c.Set(ctx, "property:id:1", []byte("3"), 0)
+				c.DeleteByPrefix(ctx, "property:list:")
+			},
+			present: []string{"property:id:1"},
+			missing: []string{"property:list:1", "property:list:2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLRUCache(tt.max)
+			tt.run(c)
+			for _, key := range tt.present {
+				if _, err := c.Get(ctx, key); err != nil {
+					t.Errorf("Get(%q) error = %v, want hit", key, err)
+				}
+			}
+			for _, key := range tt.missing {
+				if _, err := c.Get(ctx, key); err != ErrMiss {
+					t.Errorf("Get(%q) error = %v, want ErrMiss", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLRUCacheOverwriteReplacesValue(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(1)
+	c.Set(ctx, "a", []byte("1"), time.Nanosecond)
+	c.Set(ctx, "a", []byte("2"), 0)
+	time.Sleep(time.Millisecond)
+
+	got, err := c.Get(ctx, "a")
+	if err != nil || string(got) != "2" {
+		t.Fatalf("Get(a) = %q, %v; want \"2\", nil", got, err)
+	}
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+type Stats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+type StatsReporter interface {
+	Stats() map[string]Stats
+}
+
+// InstrumentedCache counts hits and misses per key namespace, which is the key
+// up to its second ':' (e.g. "property:list").
+type InstrumentedCache struct {
+	Cache
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+func NewInstrumentedCache(c Cache) *InstrumentedCache {
+	return &InstrumentedCache{Cache: c, stats: make(map[string]*Stats)}
+}
+
+func (c *InstrumentedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.Cache.Get(ctx, key)
+	if err == nil || err == ErrMiss {
+		c.record(key, err == nil)
+	}
+	return value, err
+}
+
+func (c *InstrumentedCache) Stats() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[string]Stats, len(c.stats))
+	for namespace, s := range c.stats {
+		snapshot[namespace] = *s
+	}
+	return snapshot
+}
+
+func (c *InstrumentedCache) record(key string, hit bool) {
+	namespace := key
+	if parts := strings.SplitN(key, ":", 3); len(parts) >= 2 {
+		namespace = parts[0] + ":" + parts[1]
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.stats[namespace]
+	if !ok {
+		s = &Stats{}
+		c.stats[namespace] = s
+	}
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+	s.HitRate = float64(s.Hits) / float64(s.Hits+s.Misses)
+}
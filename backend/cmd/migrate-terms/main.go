@@ -0,0 +1,30 @@
+// Command migrate-terms converts the amenities and tags of existing
+// properties from comma separated strings to normalized arrays.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/dcode-github/property_lisitng_system/backend/config"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Error loading .env file: %v", err)
+	}
+
+	client, err := config.ConnectDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer config.CloseDBConnection(client)
+
+	migrated, err := store.MigrateTermLists(context.Background(), config.Database(client))
+	if err != nil {
+		log.Fatalf("Migration failed after %d properties: %v", migrated, err)
+	}
+	log.Printf("Migrated amenities and tags of %d properties", migrated)
+}
@@ -0,0 +1,30 @@
+// Command migrate-users marks accounts created before email verification as
+// verified and normalizes their emails.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/dcode-github/property_lisitng_system/backend/config"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Error loading .env file: %v", err)
+	}
+
+	client, err := config.ConnectDB()
+	if err != nil {
+		log.Fatalf("Failed to connect to the database: %v", err)
+	}
+	defer config.CloseDBConnection(client)
+
+	verified, normalized, err := store.MigrateUsers(context.Background(), config.Database(client))
+	if err != nil {
+		log.Fatalf("Migration failed after marking %d users verified and normalizing %d emails: %v", verified, normalized, err)
+	}
+	log.Printf("Marked %d existing users verified and normalized %d emails", verified, normalized)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+)
+
+// envDuration reads a positive Go duration such as "15m" or "720h", falling
+// back when it is unset or malformed.
+func envDuration(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Ignoring invalid %s %q, using %s", name, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// TokenLifetimes reads ACCESS_TOKEN_TTL, REFRESH_TOKEN_TTL,
+// PASSWORD_RESET_TTL and EMAIL_VERIFICATION_TTL.
+func TokenLifetimes() utils.TokenLifetimes {
+	return utils.TokenLifetimes{
+		Access:            envDuration("ACCESS_TOKEN_TTL", utils.DefaultTokenLifetimes.Access),
+		Refresh:           envDuration("REFRESH_TOKEN_TTL", utils.DefaultTokenLifetimes.Refresh),
+		PasswordReset:     envDuration("PASSWORD_RESET_TTL", utils.DefaultTokenLifetimes.PasswordReset),
+		EmailVerification: envDuration("EMAIL_VERIFICATION_TTL", utils.DefaultTokenLifetimes.EmailVerification),
+	}
+}
+
+// AdminUserIDs reads ADMIN_USER_IDS, a comma separated list of user IDs that
+// are given the admin role when they register.
+func AdminUserIDs() []string {
+	var ids []string
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -5,19 +5,25 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var (
-	UserCollection           *mongo.Collection
-	PropertyCollection       *mongo.Collection
-	FavoriteCollection       *mongo.Collection
-	RecommendationCollection *mongo.Collection
+const (
+	StoreBackendMongo  = "mongo"
+	StoreBackendMemory = "memory"
 )
 
+func StoreBackend() string {
+	if strings.EqualFold(os.Getenv("STORE_BACKEND"), StoreBackendMemory) {
+		return StoreBackendMemory
+	}
+	return StoreBackendMongo
+}
+
 func ConnectDB() (*mongo.Client, error) {
 	MONGO_URI := os.Getenv("MONGOURI")
 	if MONGO_URI == "" {
@@ -42,12 +48,8 @@ func ConnectDB() (*mongo.Client, error) {
 	return client, nil
 }
 
-func InitCollections(client *mongo.Client) {
-	dbName := os.Getenv("DB")
-	UserCollection = client.Database(dbName).Collection("users")
-	PropertyCollection = client.Database(dbName).Collection("properties")
-	FavoriteCollection = client.Database(dbName).Collection("favorites")
-	RecommendationCollection = client.Database(dbName).Collection("recommendations")
+func Database(client *mongo.Client) *mongo.Database {
+	return client.Database(os.Getenv("DB"))
 }
 
 func CloseDBConnection(client *mongo.Client) {
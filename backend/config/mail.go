@@ -0,0 +1,19 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/dcode-github/property_lisitng_system/backend/mail"
+)
+
+// InitMailer appends outgoing mail to MAIL_FILE when it is set and logs it
+// otherwise.
+func InitMailer() mail.Mailer {
+	if path := os.Getenv("MAIL_FILE"); path != "" {
+		log.Printf("Writing outgoing mail to %s", path)
+		return mail.NewFileMailer(path)
+	}
+	log.Println("MAIL_FILE is not set, outgoing mail is written to the log")
+	return mail.LogMailer{}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/middleware"
+)
+
+const (
+	defaultRateLimitPerMinute     = 300
+	defaultAnonRateLimitPerMinute = 60
+)
+
+// envInt reads a non-negative integer setting, falling back when it is unset
+// or malformed.
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(name)); err == nil && v >= 0 {
+		return v
+	}
+	return fallback
+}
+
+// InitRateLimiter builds the per-client request limiter. Anonymous callers
+// are limited by IP with ANON_RATE_LIMIT_PER_MINUTE, signed in users by user
+// ID with RATE_LIMIT_PER_MINUTE. Zero disables the respective limit.
+func InitRateLimiter() *middleware.RateLimiter {
+	return middleware.NewRateLimiter(
+		time.Minute,
+		envInt("RATE_LIMIT_PER_MINUTE", defaultRateLimitPerMinute),
+		envInt("ANON_RATE_LIMIT_PER_MINUTE", defaultAnonRateLimitPerMinute),
+	)
+}
@@ -2,38 +2,69 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"sync"
 
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
 	"github.com/redis/go-redis/v9"
 )
 
+const defaultCacheMaxEntries = 10000
+
 var (
 	redisClient *redis.Client
+	redisErr    error
 	redisOnce   sync.Once
 	Ctx         = context.Background()
 )
 
-func InitRedis() *redis.Client {
+func InitRedis() (*redis.Client, error) {
 	redisOnce.Do(func() {
 		redisURL := os.Getenv("REDIS_URL")
 		if redisURL == "" {
-			log.Fatal("❌ REDIS_URL is not set")
+			redisErr = fmt.Errorf("REDIS_URL is not set")
+			return
 		}
 
 		options, err := redis.ParseURL(redisURL)
 		if err != nil {
-			log.Fatalf("❌ Failed to parse REDIS_URL: %v", err)
+			redisErr = fmt.Errorf("failed to parse REDIS_URL: %v", err)
+			return
 		}
 
-		redisClient = redis.NewClient(options)
+		client := redis.NewClient(options)
 
-		if _, err := redisClient.Ping(Ctx).Result(); err != nil {
-			log.Fatalf("❌ Failed to connect to Redis: %v", err)
+		if _, err := client.Ping(Ctx).Result(); err != nil {
+			client.Close()
+			redisErr = fmt.Errorf("failed to connect to Redis: %v", err)
+			return
 		}
+		redisClient = client
 		log.Println("✅ Connected to Redis")
 	})
 
-	return redisClient
+	return redisClient, redisErr
+}
+
+// InitCache prefers Redis and falls back to a bounded in-process LRU cache so
+// the API keeps serving when Redis is not configured or unreachable.
+func InitCache() (cache.Cache, func()) {
+	client, err := InitRedis()
+	if err != nil {
+		maxEntries := defaultCacheMaxEntries
+		if v, convErr := strconv.Atoi(os.Getenv("CACHE_MAX_ENTRIES")); convErr == nil && v > 0 {
+			maxEntries = v
+		}
+		log.Printf("⚠️ Redis unavailable (%v), using in-memory LRU cache with %d entries", err, maxEntries)
+		return cache.NewInstrumentedCache(cache.NewLRUCache(maxEntries)), func() {}
+	}
+
+	return cache.NewInstrumentedCache(cache.NewRedisCache(client)), func() {
+		if err := client.Close(); err != nil {
+			log.Printf("Error closing Redis connection: %v", err)
+		}
+	}
 }
@@ -5,24 +5,34 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/dcode-github/property_lisitng_system/backend/config"
+	"github.com/dcode-github/property_lisitng_system/backend/mail"
 	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
 	"github.com/dcode-github/property_lisitng_system/backend/utils"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/dcode-github/property_lisitng_system/backend/validation"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Response struct {
-	Message string `json:"message"`
-	Token   string `json:"token,omitempty"`
+	Message      string `json:"message"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	ExpiresIn    int64  `json:"expiresIn,omitempty"`
 }
 
 type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
-func RegisterUser() http.HandlerFunc {
+// RegisterUser gives new users RoleUser, except for the IDs in adminUserIDs
+// which are registered as admins to bootstrap a deployment. New accounts are
+// unverified until the token mailed to them is passed to VerifyEmail.
+func RegisterUser(users store.UserStore, tokens store.OneTimeTokenStore, mailer mail.Mailer, lifetimes utils.TokenLifetimes, adminUserIDs []string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var user models.User
 		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
@@ -30,20 +40,35 @@ func RegisterUser() http.HandlerFunc {
 			http.Error(w, "Invalid request payload", http.StatusBadRequest)
 			return
 		}
+		user.Email = models.NormalizeEmail(user.Email)
+		if errs := validation.Struct(&user); len(errs) > 0 {
+			writeValidationError(w, "RegisterUser", errs)
+			return
+		}
 
-		exists := config.UserCollection.FindOne(context.TODO(), bson.M{"userID": user.UserID})
-		if exists.Err() == nil {
+		_, err := users.FindByUserID(r.Context(), user.UserID)
+		if err == nil {
 			log.Printf("UserID already exists: %s", user.UserID)
 			http.Error(w, "UserID already exists", http.StatusConflict)
 			return
 		}
+		if err != store.ErrNotFound {
+			log.Printf("Error checking userID %s: %v", user.UserID, err)
+			http.Error(w, "Failed to check user", http.StatusInternalServerError)
+			return
+		}
 
-		exists = config.UserCollection.FindOne(context.TODO(), bson.M{"email": user.Email})
-		if exists.Err() == nil {
+		_, err = users.FindByEmail(r.Context(), user.Email)
+		if err == nil {
 			log.Printf("User email already exists: %s", user.Email)
 			http.Error(w, "Email already exists", http.StatusConflict)
 			return
 		}
+		if err != store.ErrNotFound {
+			log.Printf("Error checking email %s: %v", user.Email, err)
+			http.Error(w, "Failed to check user", http.StatusInternalServerError)
+			return
+		}
 
 		hashedPwd, err := utils.HashPassword(user.Password)
 		if err != nil {
@@ -53,20 +78,30 @@ func RegisterUser() http.HandlerFunc {
 		}
 		user.Password = hashedPwd
 		user.CreatedAt = time.Now()
+		user.Verified = false
+		user.Role = models.RoleUser
+		if slices.Contains(adminUserIDs, user.UserID) {
+			user.Role = models.RoleAdmin
+		}
 
-		_, err = config.UserCollection.InsertOne(context.TODO(), user)
-		if err != nil {
+		if err := users.Create(r.Context(), &user); err != nil {
 			log.Printf("Error inserting user into the database: %v", err)
 			http.Error(w, "Failed to create user", http.StatusInternalServerError)
 			return
 		}
 
+		// The account exists either way, and a new token can be requested
+		// from ResendEmailVerification.
+		if err := sendEmailVerification(r.Context(), tokens, mailer, lifetimes, &user); err != nil {
+			log.Printf("Failed to send email verification to user %s: %v", user.UserID, err)
+		}
+
 		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(Response{Message: "User registered successfully"})
+		json.NewEncoder(w).Encode(Response{Message: "User registered successfully, check your email to verify your address"})
 	}
 }
 
-func LoginUser() http.HandlerFunc {
+func LoginUser(users store.UserStore, refreshTokens store.RefreshTokenStore, lifetimes utils.TokenLifetimes) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var credentials models.User
 		if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
@@ -75,8 +110,7 @@ func LoginUser() http.HandlerFunc {
 			return
 		}
 
-		var dbUser models.User
-		err := config.UserCollection.FindOne(context.TODO(), bson.M{"userID": credentials.UserID}).Decode(&dbUser)
+		dbUser, err := users.FindByUserID(r.Context(), credentials.UserID)
 		if err != nil {
 			log.Printf("User not found: %s", credentials.UserID)
 			http.Error(w, "User not found", http.StatusUnauthorized)
@@ -89,13 +123,263 @@ func LoginUser() http.HandlerFunc {
 			return
 		}
 
-		token, err := utils.GenerateJWT(dbUser.UserID)
+		// Each login starts a new refresh token family.
+		response, err := issueTokens(r.Context(), refreshTokens, lifetimes, dbUser, primitive.NewObjectID().Hex())
+		if err != nil {
+			log.Printf("Error generating tokens for user %s: %v", dbUser.UserID, err)
+			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+			return
+		}
+
+		response.Message = "Login successful"
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// RefreshAccessToken exchanges a refresh token for a new access token and a
+// new refresh token. Each refresh token can be exchanged once; presenting it
+// again revokes every token of its family, logging out both the legitimate
+// client and whoever copied the token.
+func RefreshAccessToken(users store.UserStore, refreshTokens store.RefreshTokenStore, lifetimes utils.TokenLifetimes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		var body struct {
+			RefreshToken string `json:"refreshToken"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RefreshToken == "" {
+			log.Printf("Invalid refresh request payload: %v", err)
+			http.Error(w, "refreshToken is required", http.StatusBadRequest)
+			return
+		}
+
+		stored, err := refreshTokens.FindByHash(requestCtx, utils.HashToken(body.RefreshToken))
+		if err == store.ErrNotFound {
+			log.Println("Unknown refresh token presented")
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Printf("Error looking up refresh token: %v", err)
+			http.Error(w, "Failed to refresh token", http.StatusInternalServerError)
+			return
+		}
+
+		now := time.Now()
+		if !now.Before(stored.ExpiresAt) {
+			http.Error(w, "Refresh token has expired", http.StatusUnauthorized)
+			return
+		}
+
+		if stored.Revoked {
+			http.Error(w, "Refresh token has been revoked", http.StatusUnauthorized)
+			return
+		}
+
+		exchanged := false
+		if stored.UsedAt == nil {
+			exchanged, err = refreshTokens.MarkUsed(requestCtx, stored.ID, now)
+			if err != nil {
+				log.Printf("Error marking refresh token %s as used: %v", stored.ID.Hex(), err)
+				http.Error(w, "Failed to refresh token", http.StatusInternalServerError)
+				return
+			}
+		}
+		if !exchanged {
+			log.Printf("Refresh token reuse detected for user %s, revoking token family %s", stored.UserID, stored.FamilyID)
+			if err := refreshTokens.RevokeFamily(requestCtx, stored.FamilyID); err != nil {
+				log.Printf("Error revoking refresh token family %s: %v", stored.FamilyID, err)
+			}
+			http.Error(w, "Refresh token was already used, please log in again", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := users.FindByUserID(requestCtx, stored.UserID)
 		if err != nil {
-			log.Printf("Error generating JWT token: %v", err)
+			log.Printf("User %s of refresh token not found: %v", stored.UserID, err)
+			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+			return
+		}
+		// A refresh token issued while the user's sessions were being revoked
+		// escapes RevokeUser but still carries the old token version.
+		if stored.TokenVersion != user.TokenVersion {
+			http.Error(w, "Refresh token has been revoked", http.StatusUnauthorized)
+			return
+		}
+
+		response, err := issueTokens(requestCtx, refreshTokens, lifetimes, user, stored.FamilyID)
+		if err != nil {
+			log.Printf("Error generating tokens for user %s: %v", stored.UserID, err)
 			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
 			return
 		}
 
-		json.NewEncoder(w).Encode(Response{Message: "Login successful", Token: token})
+		response.Message = "Token refreshed"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// issueTokens signs an access token for user and stores a new refresh token
+// in the given family, both carrying the user's current token version.
+func issueTokens(ctx context.Context, refreshTokens store.RefreshTokenStore, lifetimes utils.TokenLifetimes, user *models.User, familyID string) (Response, error) {
+	accessToken, err := utils.GenerateJWT(user.UserID, user.EffectiveRole(), familyID, user.TokenVersion, lifetimes.Access)
+	if err != nil {
+		return Response{}, err
+	}
+
+	refreshToken, hash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return Response{}, err
+	}
+	now := time.Now()
+	if err := refreshTokens.Create(ctx, &models.RefreshToken{
+		UserID:       user.UserID,
+		FamilyID:     familyID,
+		TokenHash:    hash,
+		ExpiresAt:    now.Add(lifetimes.Refresh),
+		CreatedAt:    now,
+		TokenVersion: user.TokenVersion,
+	}); err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(lifetimes.Access.Seconds()),
+	}, nil
+}
+
+// Logout revokes the caller's access token and the refresh tokens of its
+// session.
+func Logout(refreshTokens store.RefreshTokenStore, revocations store.RevocationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		claims, ok := requestCtx.Value(ClaimsKey).(*utils.Claims)
+		if !ok {
+			log.Println("Token claims missing in context for Logout")
+			http.Error(w, "Token claims missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		if claims.Id != "" {
+			if err := revocations.RevokeToken(requestCtx, claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+				log.Printf("Failed to revoke token of user %s: %v", claims.UserID, err)
+				http.Error(w, "Failed to log out", http.StatusInternalServerError)
+				return
+			}
+		}
+		if claims.SessionID != "" {
+			if err := refreshTokens.RevokeFamily(requestCtx, claims.SessionID); err != nil {
+				log.Printf("Failed to revoke session %s of user %s: %v", claims.SessionID, claims.UserID, err)
+				http.Error(w, "Failed to log out", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Response{Message: "Logged out"})
+	}
+}
+
+// LogoutAll revokes every access and refresh token of the caller.
+func LogoutAll(refreshTokens store.RefreshTokenStore, revocations store.RevocationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		claims, ok := requestCtx.Value(ClaimsKey).(*utils.Claims)
+		if !ok {
+			log.Println("Token claims missing in context for LogoutAll")
+			http.Error(w, "Token claims missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		if err := revokeUserSessions(requestCtx, refreshTokens, revocations, claims.UserID); err != nil {
+			log.Printf("Failed to revoke sessions of user %s: %v", claims.UserID, err)
+			http.Error(w, "Failed to log out", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Response{Message: "Logged out of all sessions"})
+	}
+}
+
+// revokeUserSessions revokes all refresh tokens of userID and raises its
+// token version, rejecting every access token issued so far. Tokens issued
+// afterwards carry the new version and stay valid.
+func revokeUserSessions(ctx context.Context, refreshTokens store.RefreshTokenStore, revocations store.RevocationStore, userID string) error {
+	if err := refreshTokens.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+	return revocations.RevokeUserTokens(ctx, userID)
+}
+
+// SetUserRole changes the role of another user. Roles are carried by access
+// tokens, so the user is logged out of every session and gets the new role
+// from their next login.
+func SetUserRole(users store.UserStore, refreshTokens store.RefreshTokenStore, revocations store.RevocationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		targetUserID := mux.Vars(r)["userID"]
+
+		var body struct {
+			Role string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			log.Printf("Invalid request body for SetUserRole: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if !slices.Contains(models.Roles, body.Role) {
+			writeValidationError(w, "SetUserRole", validation.Errors{{Field: "role", Reason: "must be one of " + strings.Join(models.Roles, ", ")}})
+			return
+		}
+
+		found, err := users.SetRole(r.Context(), targetUserID, body.Role)
+		if err != nil {
+			log.Printf("Failed to set role of user %s: %v", targetUserID, err)
+			http.Error(w, "Failed to set role", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+
+		if err := revokeUserSessions(r.Context(), refreshTokens, revocations, targetUserID); err != nil {
+			log.Printf("Failed to revoke sessions of user %s after role change: %v", targetUserID, err)
+			http.Error(w, "Role was updated but existing sessions could not be revoked", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Role of user %s set to %s", targetUserID, body.Role)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Response{Message: "Role updated"})
+	}
+}
+
+// issueOneTimeToken stores a new token for userID and purpose, replacing the
+// previous ones, and returns the plain token to mail to the user.
+func issueOneTimeToken(ctx context.Context, tokens store.OneTimeTokenStore, userID, purpose string, ttl time.Duration) (string, error) {
+	if err := tokens.DeleteByUser(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+	token, hash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	err = tokens.Create(ctx, &models.OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
 	}
+	return token, nil
 }
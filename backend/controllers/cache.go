@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cached responses embed the generation of the data they were built from.
+// Writes bump the relevant generation instead of deleting keys, so stale
+// entries are never read again and simply age out through their TTL.
+const (
+	propertyVersionKey               = "property:version"
+	propertyIDVersionPrefix          = "property:version:id:"
+	userFavoritesVersionPrefix       = "favorites:version:user:"
+	userRecommendationsVersionPrefix = "recommendations:version:user:"
+
+	// versionKeyTTL lets version keys of idle users and listings expire. It
+	// is far longer than any entry built from a version (TTL plus stale
+	// window), so entries expire before the version they were keyed on.
+	versionKeyTTL = 24 * time.Hour
+)
+
+// propertyIDVersionKey tracks a single listing so that its detail entries
+// survive writes to other listings.
+func propertyIDVersionKey(propertyID primitive.ObjectID) string {
+	return propertyIDVersionPrefix + propertyID.Hex()
+}
+
+func userFavoritesVersionKey(userID string) string {
+	return userFavoritesVersionPrefix + userID
+}
+
+func userRecommendationsVersionKey(userID string) string {
+	return userRecommendationsVersionPrefix + userID
+}
+
+func newCacheVersion() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// cacheVersions returns the current generation for each key, initialising
+// missing ones. A version is seeded from the clock rather than starting at
+// zero so an evicted counter can never resurrect entries of an older
+// generation. ok is false when the cache is unavailable.
+func cacheVersions(ctx context.Context, appCache cache.Cache, keys ...string) ([]string, bool) {
+	versions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, err := appCache.Get(ctx, key)
+		if err == nil {
+			versions = append(versions, string(value))
+			continue
+		}
+		if err != cache.ErrMiss {
+			log.Printf("Cache GET error for version key %s: %v", key, err)
+			return nil, false
+		}
+
+		version := newCacheVersion()
+		if err := appCache.Set(ctx, key, []byte(version), versionKeyTTL); err != nil {
+			log.Printf("Failed to initialise cache version key %s: %v", key, err)
+			return nil, false
+		}
+		versions = append(versions, version)
+	}
+	return versions, true
+}
+
+func bumpCacheVersion(ctx context.Context, appCache cache.Cache, key string) {
+	if err := appCache.Set(ctx, key, []byte(newCacheVersion()), versionKeyTTL); err != nil {
+		log.Printf("Failed to bump cache version key %s: %v", key, err)
+		return
+	}
+	log.Printf("Bumped cache version key: %s", key)
+}
+
+// loadCached returns the response stored under cacheKey, building it with
+// load on a miss. When the cache versions could not be read the response is
+// built directly and not cached.
+func loadCached(ctx context.Context, loader *cache.Loader, cacheable bool, cacheKey string, opts cache.LoadOptions, handlerName string, load cache.LoadFunc) ([]byte, error) {
+	if !cacheable {
+		return load(ctx)
+	}
+
+	value, outcome, err := loader.Fetch(ctx, cacheKey, opts, load)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Cache %s for %s key: %s", outcome, handlerName, cacheKey)
+	return value, nil
+}
+
+func GetCacheStats(appCache cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reporter, ok := appCache.(cache.StatsReporter)
+		if !ok {
+			http.Error(w, "Cache statistics are not available", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: "Fetched cache statistics",
+			Data:    reporter.Stats(),
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/dcode-github/property_lisitng_system/backend/mail"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+	"github.com/dcode-github/property_lisitng_system/backend/validation"
+)
+
+// sendEmailVerification mails user a token that proves they own their email
+// address, replacing any token sent earlier.
+func sendEmailVerification(ctx context.Context, tokens store.OneTimeTokenStore, mailer mail.Mailer, lifetimes utils.TokenLifetimes, user *models.User) error {
+	token, err := issueOneTimeToken(ctx, tokens, user.UserID, models.TokenPurposeEmailVerification, lifetimes.EmailVerification)
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Welcome, %s.\n\n"+
+			"Verification token: %s\n\n"+
+			"The token can be used once and expires in %s. Until the address is verified, other users cannot send you recommendations.",
+			user.UserID, token, lifetimes.EmailVerification),
+	})
+}
+
+// VerifyEmail marks the account a mailed verification token was issued for
+// as verified.
+func VerifyEmail(users store.UserStore, tokens store.OneTimeTokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		var body struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			log.Printf("Invalid request body for VerifyEmail: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if body.Token == "" {
+			writeValidationError(w, "VerifyEmail", validation.Errors{{Field: "token", Reason: "is required"}})
+			return
+		}
+
+		token, err := tokens.Consume(requestCtx, utils.HashToken(body.Token), models.TokenPurposeEmailVerification)
+		if err == store.ErrNotFound {
+			http.Error(w, "Invalid or expired verification token", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to consume email verification token: %v", err)
+			http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+			return
+		}
+
+		found, err := users.SetVerified(requestCtx, token.UserID)
+		if err != nil {
+			log.Printf("Failed to mark email of user %s as verified: %v", token.UserID, err)
+			http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "Invalid or expired verification token", http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("Email of user %s verified", token.UserID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Response{Message: "Email verified"})
+	}
+}
+
+// ResendEmailVerification mails the caller a new verification token.
+func ResendEmailVerification(users store.UserStore, tokens store.OneTimeTokenStore, mailer mail.Mailer, lifetimes utils.TokenLifetimes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for ResendEmailVerification")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := users.FindByUserID(requestCtx, userID)
+		if err == store.ErrNotFound {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Error loading user %s: %v", userID, err)
+			http.Error(w, "Failed to load user", http.StatusInternalServerError)
+			return
+		}
+		if user.Verified {
+			http.Error(w, "Email is already verified", http.StatusConflict)
+			return
+		}
+
+		if err := sendEmailVerification(requestCtx, tokens, mailer, lifetimes, user); err != nil {
+			log.Printf("Failed to send email verification to user %s: %v", userID, err)
+			http.Error(w, "Failed to send verification email", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(Response{Message: "Verification email sent"})
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+)
+
+const (
+	propertyFacetsCachePrefix = "property:facets:"
+	maxFacetTerms             = 50
+)
+
+// Term facets count listings per distinct value; range facets count them per
+// bucket, the last bucket being open-ended. Listings without a numeric value
+// at or above the first boundary are not counted in any bucket.
+var (
+	termFacetFields = map[string]bool{
+		"city": true, "state": true, "type": true, "furnished": true, "listedBy": true,
+		"listingType": true, "bedrooms": true, "bathrooms": true, "isVerified": true,
+		"verificationStatus": true,
+	}
+	rangeFacetBoundaries = map[string][]float64{
+		"price":    {0, 1000000, 2500000, 5000000, 10000000, 20000000},
+		"areaSqFt": {0, 500, 1000, 1500, 2000, 3000},
+	}
+)
+
+// parseFacetRequest reads the comma separated facets parameter. It returns a
+// zero request when no facets were asked for.
+func parseFacetRequest(query url.Values) (store.FacetRequest, error) {
+	req := store.FacetRequest{MaxTerms: maxFacetTerms}
+	v := query.Get("facets")
+	if v == "" {
+		return req, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(v, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+
+		switch {
+		case termFacetFields[field]:
+			req.Terms = append(req.Terms, field)
+		case rangeFacetBoundaries[field] != nil:
+			if req.Ranges == nil {
+				req.Ranges = make(map[string][]float64)
+			}
+			req.Ranges[field] = rangeFacetBoundaries[field]
+		default:
+			return req, fmt.Errorf("facets on %q are not supported", field)
+		}
+	}
+	return req, nil
+}
+
+func hasFacets(req store.FacetRequest) bool {
+	return len(req.Terms) > 0 || len(req.Ranges) > 0
+}
+
+// generateCacheKeyForPropertyFacets keys facet counts on the filter alone, so
+// every page of a search shares them.
+func generateCacheKeyForPropertyFacets(queryParams url.Values, versions []string) string {
+	filterParams := url.Values{}
+	for key, values := range queryParams {
+		if !paginationParams[key] {
+			filterParams[key] = append([]string(nil), values...)
+		}
+	}
+	return propertyFacetsCachePrefix + strings.Join(versions, ":") + ":" + hashQuery(filterParams)
+}
@@ -5,37 +5,71 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
-	"github.com/dcode-github/property_lisitng_system/backend/config"
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
 	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
 	"github.com/gorilla/mux"
-	"github.com/redis/go-redis/v9"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
-	userFavoritesCachePrefix = "favorites:user:"
-	favoriteCacheTTL         = 10 * time.Minute
+	userFavoritesCachePrefix   = "favorites:user:"
+	userFavoriteIDsCachePrefix = "favorites:ids:user:"
+	favoriteCacheTTL           = 10 * time.Minute
 )
 
-func generateUserFavoritesCacheKey(userID string) string {
-	return userFavoritesCachePrefix + userID
+var favoriteCacheOptions = cache.LoadOptions{TTL: favoriteCacheTTL, StaleTTL: defaultStaleTTL}
+
+func generateUserFavoritesCacheKey(userID string, versions []string, query url.Values) string {
+	return userFavoritesCachePrefix + userID + ":" + strings.Join(versions, ":") + ":" + hashQuery(query)
+}
+
+func generateUserFavoriteIDsCacheKey(userID string, versions []string) string {
+	return userFavoriteIDsCachePrefix + userID + ":" + strings.Join(versions, ":")
 }
 
-func deleteUserFavoritesCache(ctx context.Context, redisClient *redis.Client, userID string) {
-	cacheKey := generateUserFavoritesCacheKey(userID)
-	err := redisClient.Del(ctx, cacheKey).Err()
-	if err != nil && err != redis.Nil {
-		log.Printf("Error deleting user favorites cache key %s: %v", cacheKey, err)
-	} else if err == nil {
-		log.Printf("Successfully deleted user favorites cache key: %s", cacheKey)
+// loadFavoriteIDSet returns the set of property IDs the user has marked as
+// favorite. The set is cached per user so shared list responses can be
+// personalised without querying favorites on every request.
+func loadFavoriteIDSet(ctx context.Context, loader *cache.Loader, appCache cache.Cache, favorites store.FavoriteStore, userID string) (map[primitive.ObjectID]bool, error) {
+	versions, cacheable := cacheVersions(ctx, appCache, userFavoritesVersionKey(userID))
+	cacheKey := generateUserFavoriteIDsCacheKey(userID, versions)
+
+	data, err := loadCached(ctx, loader, cacheable, cacheKey, favoriteCacheOptions, "FavoriteIDs", func(ctx context.Context) ([]byte, error) {
+		propertyIDs, err := favorites.ListPropertyIDs(ctx, userID)
+		if err != nil {
+			log.Printf("Failed to fetch favorite property IDs for user %s: %v", userID, err)
+			return nil, err
+		}
+		return json.Marshal(propertyIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var propertyIDs []primitive.ObjectID
+	if err := json.Unmarshal(data, &propertyIDs); err != nil {
+		return nil, err
+	}
+
+	favSet := make(map[primitive.ObjectID]bool, len(propertyIDs))
+	for _, id := range propertyIDs {
+		favSet[id] = true
 	}
+	return favSet, nil
+}
+
+// invalidateUserFavoritesCache drops the user's favorites list and favorite ID
+// set, leaving other users' entries intact.
+func invalidateUserFavoritesCache(ctx context.Context, appCache cache.Cache, userID string) {
+	bumpCacheVersion(ctx, appCache, userFavoritesVersionKey(userID))
 }
 
-func AddFavorite(redisClient *redis.Client) http.HandlerFunc {
+func AddFavorite(favorites store.FavoriteStore, appCache cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -65,29 +99,26 @@ func AddFavorite(redisClient *redis.Client) http.HandlerFunc {
 			PropertyID: favInput.PropertyID,
 		}
 
-		err := config.FavoriteCollection.FindOne(requestCtx, bson.M{"userID": userID, "propertyID": favToSave.PropertyID}).Err()
-		if err == nil {
-			log.Printf("Property %s is already in favorites for user %s", favToSave.PropertyID.Hex(), userID)
-			http.Error(w, "Property is already in favorites", http.StatusConflict)
-			return
-		}
-		if err != mongo.ErrNoDocuments {
+		exists, err := favorites.Exists(requestCtx, userID, favToSave.PropertyID)
+		if err != nil {
 			log.Printf("Failed to check favorites for user %s, property %s: %v", userID, favToSave.PropertyID.Hex(), err)
 			http.Error(w, "Failed to check favorites", http.StatusInternalServerError)
 			return
 		}
+		if exists {
+			log.Printf("Property %s is already in favorites for user %s", favToSave.PropertyID.Hex(), userID)
+			http.Error(w, "Property is already in favorites", http.StatusConflict)
+			return
+		}
 
-		_, err = config.FavoriteCollection.InsertOne(requestCtx, favToSave)
-		if err != nil {
+		if err := favorites.Add(requestCtx, &favToSave); err != nil {
 			log.Printf("Failed to add property %s to favorites for user %s: %v", favToSave.PropertyID.Hex(), userID, err)
 			http.Error(w, "Failed to add property to favorites", http.StatusInternalServerError)
 			return
 		}
 
 		go func() {
-			deleteUserFavoritesCache(context.Background(), redisClient, userID)
-
-			deletePropertyCache(redisClient)
+			invalidateUserFavoritesCache(context.Background(), appCache, userID)
 			log.Printf("Caches invalidated after adding favorite for user %s, property %s", userID, favToSave.PropertyID.Hex())
 		}()
 
@@ -101,7 +132,9 @@ func AddFavorite(redisClient *redis.Client) http.HandlerFunc {
 	}
 }
 
-func GetFavorites(redisClient *redis.Client) http.HandlerFunc {
+func GetFavorites(favorites store.FavoriteStore, appCache cache.Cache) http.HandlerFunc {
+	loader := cache.NewLoader(appCache)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -112,73 +145,51 @@ func GetFavorites(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
-		cacheKey := generateUserFavoritesCacheKey(userID)
-		cachedData, err := redisClient.Get(requestCtx, cacheKey).Result()
-
-		if err == nil {
-			log.Printf("Cache Hit for GetFavorites, user %s, key %s", userID, cacheKey)
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(cachedData))
-			return
-		}
-		if err != redis.Nil {
-			log.Printf("Redis GET error for GetFavorites user %s, key %s: %v. Fetching from DB.", userID, cacheKey, err)
-		}
-
-		log.Printf("Cache Miss for GetFavorites, user %s, key %s", userID, cacheKey)
-
-		pipeline := mongo.Pipeline{
-			{{Key: "$match", Value: bson.M{"userID": userID}}},
-			{{Key: "$lookup", Value: bson.M{
-				"from":         config.PropertyCollection.Name(),
-				"localField":   "propertyID",
-				"foreignField": "_id",
-				"as":           "propertyDetails",
-			}}},
-			{{Key: "$unwind", Value: "$propertyDetails"}},
-			{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$propertyDetails"}}},
-			{{Key: "$addFields", Value: bson.M{"isFavorite": true}}},
-		}
-
-		cursor, err := config.FavoriteCollection.Aggregate(requestCtx, pipeline)
+		query := r.URL.Query()
+		page, listFilter, err := parseListRequest(query)
 		if err != nil {
-			log.Printf("Failed to fetch favorite properties for user %s via aggregation: %v", userID, err)
-			http.Error(w, "Failed to fetch favorite properties", http.StatusInternalServerError)
-			return
-		}
-		defer cursor.Close(requestCtx)
-
-		var properties []models.Property
-		if err := cursor.All(requestCtx, &properties); err != nil {
-			log.Printf("Failed to decode favorite properties for user %s: %v", userID, err)
-			http.Error(w, "Failed to decode favorite properties", http.StatusInternalServerError)
+			writeQueryError(w, "GetFavorites", err)
 			return
 		}
 
-		response := models.APIResponse{
-			Success: true,
-			Message: "Fetched favorite properties",
-			Data:    properties,
-		}
-
-		responseBytes, err := json.Marshal(response)
+		versions, cacheable := cacheVersions(requestCtx, appCache, propertyVersionKey, userFavoritesVersionKey(userID))
+		cacheKey := generateUserFavoritesCacheKey(userID, versions, query)
+
+		responseBytes, err := loadCached(requestCtx, loader, cacheable, cacheKey, favoriteCacheOptions, "GetFavorites", func(ctx context.Context) ([]byte, error) {
+			results, err := favorites.ListProperties(ctx, userID, page.keysetFilter(listFilter), page.findOptions())
+			if err != nil {
+				log.Printf("Failed to fetch favorite properties for user %s: %v", userID, err)
+				return nil, err
+			}
+
+			total, err := favorites.CountProperties(ctx, userID, listFilter)
+			if err != nil {
+				log.Printf("Failed to count favorite properties for user %s: %v", userID, err)
+				return nil, err
+			}
+
+			response, err := page.response(page.buildPage(results, total), "Fetched favorite properties")
+			if err != nil {
+				return nil, err
+			}
+
+			responseBytes, err := json.Marshal(response)
+			if err != nil {
+				log.Printf("Failed to marshal GetFavorites response for user %s: %v", userID, err)
+			}
+			return responseBytes, err
+		})
 		if err != nil {
-			log.Printf("Failed to marshal GetFavorites response for user %s: %v", userID, err)
-			http.Error(w, "Error processing response data", http.StatusInternalServerError)
+			http.Error(w, "Failed to fetch favorite properties", http.StatusInternalServerError)
 			return
 		}
 
-		err = redisClient.Set(requestCtx, cacheKey, responseBytes, favoriteCacheTTL).Err()
-		if err != nil {
-			log.Printf("Failed to cache GetFavorites response for user %s, key %s: %v", userID, cacheKey, err)
-		}
-
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(responseBytes)
 	}
 }
 
-func DeleteFavorite(redisClient *redis.Client) http.HandlerFunc {
+func DeleteFavorite(favorites store.FavoriteStore, appCache cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -199,26 +210,21 @@ func DeleteFavorite(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
-		deleteResult, err := config.FavoriteCollection.DeleteOne(requestCtx, bson.M{
-			"userID":     userID,
-			"propertyID": propertyObjID,
-		})
+		removed, err := favorites.Remove(requestCtx, userID, propertyObjID)
 		if err != nil {
 			log.Printf("Failed to remove property %s from favorites for user %s: %v", propertyIDHex, userID, err)
 			http.Error(w, "Failed to remove property from favorites", http.StatusInternalServerError)
 			return
 		}
 
-		if deleteResult.DeletedCount == 0 {
+		if !removed {
 			log.Printf("Favorite not found for property %s, user %s. Nothing to delete.", propertyIDHex, userID)
 			http.Error(w, "Favorite not found", http.StatusNotFound)
 			return
 		}
 
 		go func() {
-			deleteUserFavoritesCache(context.Background(), redisClient, userID)
-
-			deletePropertyCache(redisClient)
+			invalidateUserFavoritesCache(context.Background(), appCache, userID)
 			log.Printf("Caches invalidated after deleting favorite for user %s, property %s", userID, propertyIDHex)
 		}()
 
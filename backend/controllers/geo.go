@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxRadiusKm = 1000
+
+var geoParams = map[string]bool{"near": true, "radiusKm": true, "bbox": true}
+
+// geoSearch restricts listings to a circle around Near and/or a bounding box
+// given as minLng,minLat,maxLng,maxLat.
+type geoSearch struct {
+	Near     *models.GeoPoint
+	RadiusKm float64
+	BBox     []float64
+}
+
+func parseGeoSearch(query url.Values) (geoSearch, error) {
+	var geo geoSearch
+
+	if v := query.Get("near"); v != "" {
+		coords, err := parseFloatList(v, 2)
+		if err != nil || !utils.ValidCoordinates(coords[0], coords[1]) {
+			return geo, fmt.Errorf("near must be lat,lng")
+		}
+		geo.Near = models.NewGeoPoint(coords[0], coords[1])
+
+		radius, err := strconv.ParseFloat(query.Get("radiusKm"), 64)
+		if err != nil || radius <= 0 || radius > maxRadiusKm {
+			return geo, fmt.Errorf("near requires radiusKm between 0 and %d", maxRadiusKm)
+		}
+		geo.RadiusKm = radius
+	} else if query.Get("radiusKm") != "" {
+		return geo, fmt.Errorf("radiusKm requires near")
+	}
+
+	if v := query.Get("bbox"); v != "" {
+		box, err := parseFloatList(v, 4)
+		if err != nil || !utils.ValidCoordinates(box[1], box[0]) || !utils.ValidCoordinates(box[3], box[2]) ||
+			box[0] >= box[2] || box[1] >= box[3] {
+			return geo, fmt.Errorf("bbox must be minLng,minLat,maxLng,maxLat")
+		}
+		geo.BBox = box
+	}
+
+	return geo, nil
+}
+
+func parseFloatList(raw string, n int) ([]float64, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("expected %d values", n)
+	}
+	values := make([]float64, n)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (g geoSearch) conditions() []bson.M {
+	var conditions []bson.M
+	if g.Near != nil {
+		conditions = append(conditions, bson.M{"location": bson.M{"$geoWithin": bson.M{
+			"$centerSphere": bson.A{bson.A{g.Near.Lng(), g.Near.Lat()}, g.RadiusKm / utils.EarthRadiusKm},
+		}}})
+	}
+	if g.BBox != nil {
+		// $box is planar like a map viewport, with edges along parallels
+		// rather than the great circles a GeoJSON polygon would use.
+		minLng, minLat, maxLng, maxLat := g.BBox[0], g.BBox[1], g.BBox[2], g.BBox[3]
+		conditions = append(conditions, bson.M{"location": bson.M{"$geoWithin": bson.M{
+			"$box": bson.A{bson.A{minLng, minLat}, bson.A{maxLng, maxLat}},
+		}}})
+	}
+	return conditions
+}
+
+// setDistances fills in the distance from Near for listings with a location.
+func (g geoSearch) setDistances(items []models.Property) {
+	if g.Near == nil {
+		return
+	}
+	for i := range items {
+		if loc := items[i].Location; loc != nil && loc.Valid() {
+			distance := math.Round(utils.HaversineKm(g.Near.Lat(), g.Near.Lng(), loc.Lat(), loc.Lng())*1000) / 1000
+			items[i].DistanceKm = &distance
+		}
+	}
+}
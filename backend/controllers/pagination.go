@@ -0,0 +1,419 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/filter"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+	relevanceSort   = "relevance"
+)
+
+// paginationParams are consumed by parsePageRequest and must be ignored when
+// building filters from the query string.
+var paginationParams = map[string]bool{
+	"limit": true, "page": true, "offset": true, "cursor": true, "sort": true, "fields": true,
+}
+
+// propertyFields are the stored property fields a client can select with the
+// fields parameter. alwaysIncludedFields are returned regardless, as are
+// recommendedBy on recommendations, score on text searches and distanceKm on
+// searches near a point.
+var (
+	propertyFields       = storedFieldNames(reflect.TypeOf(models.Property{}))
+	alwaysIncludedFields = []string{"_id", "isFav"}
+)
+
+type pageRequest struct {
+	Limit     int64
+	Offset    int64
+	Sort      []store.SortField
+	Fields    []string
+	Cursor    *pageCursor
+	TextScore bool
+}
+
+// pageCursor is the keyset position after which the next page starts: the
+// sort key values and _id of the last item returned. A nil value stands for a
+// missing or null field. It is handed to clients as an opaque base64 string
+// and is only valid for the sort it was issued for.
+type pageCursor struct {
+	Sort   string             `json:"s,omitempty"`
+	Values []interface{}      `json:"v,omitempty"`
+	ID     primitive.ObjectID `json:"id"`
+}
+
+// propertyPage is the cached, user-independent form of a paginated list.
+type propertyPage struct {
+	Items      []models.Property `json:"items"`
+	Total      int64             `json:"total"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+	HasMore    bool              `json:"hasMore"`
+}
+
+func parsePageRequest(query url.Values) (pageRequest, error) {
+	page := pageRequest{Limit: defaultPageSize}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || limit < 1 {
+			return page, fmt.Errorf("limit must be a positive integer")
+		}
+		page.Limit = min(limit, maxPageSize)
+	}
+
+	offsetSet := false
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+		offsetSet = true
+	}
+
+	if v := query.Get("page"); v != "" {
+		if offsetSet {
+			return page, fmt.Errorf("page and offset cannot be combined")
+		}
+		pageNumber, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || pageNumber < 1 {
+			return page, fmt.Errorf("page must be a positive integer")
+		}
+		page.Offset = (pageNumber - 1) * page.Limit
+		offsetSet = true
+	}
+
+	sortFields, err := parseSort(query.Get("sort"))
+	if err != nil {
+		return page, err
+	}
+	page.Sort = sortFields
+	if page.sortsByRelevance() && strings.TrimSpace(query.Get("q")) == "" {
+		return page, fmt.Errorf("sort by relevance requires q")
+	}
+
+	fields, err := parseFields(query.Get("fields"))
+	if err != nil {
+		return page, err
+	}
+	page.Fields = fields
+
+	if v := query.Get("cursor"); v != "" {
+		if offsetSet {
+			return page, fmt.Errorf("cursor cannot be combined with page or offset")
+		}
+		if page.sortsByRelevance() {
+			return page, fmt.Errorf("cursor cannot be combined with sort by relevance, use page or offset")
+		}
+		cursor, err := decodeCursor(v, page.Sort)
+		if err != nil {
+			return page, fmt.Errorf("invalid cursor")
+		}
+		page.Cursor = &cursor
+	}
+
+	return page, nil
+}
+
+// parseSort reads a comma separated list of fields, each optionally prefixed
+// with '-' for descending order, e.g. "-price,rating". Only numeric and date
+// fields can be sorted on, plus "relevance" which orders text search results
+// by descending score.
+func parseSort(raw string) ([]store.SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var sortFields []store.SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		sf := store.SortField{Field: strings.TrimLeft(part, "+-"), Descending: strings.HasPrefix(part, "-")}
+		switch {
+		case part == relevanceSort:
+			sf = store.SortField{Field: store.TextScoreField, Descending: true}
+		case !filter.PropertySchema.Sortable(sf.Field):
+			return nil, fmt.Errorf("cannot sort by %q", sf.Field)
+		}
+		if seen[sf.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", sf.Field)
+		}
+		seen[sf.Field] = true
+		sortFields = append(sortFields, sf)
+	}
+	return sortFields, nil
+}
+
+func (p pageRequest) sortsByRelevance() bool {
+	for _, sf := range p.Sort {
+		if sf.Field == store.TextScoreField {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFields(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(propertyFields, field) && !slices.Contains(alwaysIncludedFields, field) {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func storedFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("bson"), ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func sortString(sortFields []store.SortField) string {
+	parts := make([]string, 0, len(sortFields))
+	for _, sf := range sortFields {
+		if sf.Descending {
+			parts = append(parts, "-"+sf.Field)
+		} else {
+			parts = append(parts, sf.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func encodeCursor(cursor pageCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(encoded string, sortFields []store.SortField) (pageCursor, error) {
+	var cursor pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.ID.IsZero() {
+		return cursor, fmt.Errorf("cursor has no position")
+	}
+	if cursor.Sort != sortString(sortFields) || len(cursor.Values) != len(sortFields) {
+		return cursor, fmt.Errorf("cursor was issued for a different sort")
+	}
+
+	// JSON loses the value types, restore them from the field tables.
+	for i, sf := range sortFields {
+		switch v := cursor.Values[i].(type) {
+		case nil:
+		case float64:
+			if filter.PropertySchema[sf.Field] != filter.Number {
+				return cursor, fmt.Errorf("unexpected cursor value for %s", sf.Field)
+			}
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil || filter.PropertySchema[sf.Field] != filter.Date {
+				return cursor, fmt.Errorf("unexpected cursor value for %s", sf.Field)
+			}
+			cursor.Values[i] = t
+		default:
+			return cursor, fmt.Errorf("unexpected cursor value for %s", sf.Field)
+		}
+	}
+	return cursor, nil
+}
+
+// keysetFilter restricts filter to documents after the cursor position in
+// sort order: (s1 > v1) OR (s1 = v1 AND s2 > v2) OR ... OR (all equal AND
+// _id > id), with '>' flipped to '<' for descending fields.
+func (p pageRequest) keysetFilter(filter bson.M) bson.M {
+	if p.Cursor == nil {
+		return filter
+	}
+
+	var branches bson.A
+	equal := bson.M{}
+	for i, sf := range p.Sort {
+		if after := afterValue(sf, p.Cursor.Values[i]); after != nil {
+			branch := bson.M{}
+			for field, value := range equal {
+				branch[field] = value
+			}
+			for key, cond := range after {
+				branch[key] = cond
+			}
+			branches = append(branches, branch)
+		}
+		// A nil value matches both null and missing fields.
+		equal[sf.Field] = p.Cursor.Values[i]
+	}
+	equal["_id"] = bson.M{"$gt": p.Cursor.ID}
+	branches = append(branches, equal)
+
+	after := bson.M{"$or": branches}
+	if len(filter) == 0 {
+		return after
+	}
+	return bson.M{"$and": bson.A{filter, after}}
+}
+
+// afterValue matches the values of sf that sort strictly after value. Mongo
+// sorts null and missing fields before any number or date, so they follow
+// every value in descending order and nothing follows them.
+func afterValue(sf store.SortField, value interface{}) bson.M {
+	switch {
+	case value == nil && sf.Descending:
+		return nil
+	case value == nil:
+		return bson.M{sf.Field: bson.M{"$ne": nil}}
+	case sf.Descending:
+		return bson.M{"$or": bson.A{
+			bson.M{sf.Field: bson.M{"$lt": value}},
+			bson.M{sf.Field: nil},
+		}}
+	}
+	return bson.M{sf.Field: bson.M{"$gt": value}}
+}
+
+// findOptions fetches one extra document to detect whether another page
+// follows. Sort fields are always loaded because the next cursor is built
+// from them.
+func (p pageRequest) findOptions() store.FindOptions {
+	opts := store.FindOptions{Limit: p.Limit + 1, Skip: p.Offset, Sort: p.Sort, TextScore: p.TextScore}
+	if len(p.Fields) > 0 {
+		opts.Fields = append(opts.Fields, p.Fields...)
+		for _, sf := range p.Sort {
+			if !slices.Contains(opts.Fields, sf.Field) {
+				opts.Fields = append(opts.Fields, sf.Field)
+			}
+		}
+	}
+	return opts
+}
+
+func (p pageRequest) buildPage(items []models.Property, total int64) propertyPage {
+	page := propertyPage{Items: items, Total: total}
+	if int64(len(items)) > p.Limit {
+		page.Items = items[:p.Limit]
+		page.HasMore = true
+		// Text scores cannot be filtered on, so relevance ordered results
+		// are only reachable by offset.
+		if !p.sortsByRelevance() {
+			page.NextCursor = encodeCursor(p.cursorAfter(page.Items[len(page.Items)-1]))
+		}
+	}
+	if page.Items == nil {
+		page.Items = []models.Property{}
+	}
+	return page
+}
+
+func (p pageRequest) cursorAfter(last models.Property) pageCursor {
+	cursor := pageCursor{Sort: sortString(p.Sort), ID: last.ID}
+	if len(p.Sort) == 0 {
+		return cursor
+	}
+
+	raw, _ := bson.Marshal(last)
+	var doc bson.M
+	bson.Unmarshal(raw, &doc)
+	for _, sf := range p.Sort {
+		var value interface{}
+		switch v := doc[sf.Field].(type) {
+		case int32:
+			value = float64(v)
+		case int64:
+			value = float64(v)
+		case float64:
+			value = v
+		case primitive.DateTime:
+			value = v.Time().UTC().Format(time.RFC3339Nano)
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	return cursor
+}
+
+func (p pageRequest) response(page propertyPage, message string) (models.PaginatedResponse, error) {
+	data, err := p.selectFields(page.Items)
+	if err != nil {
+		return models.PaginatedResponse{}, err
+	}
+	return models.PaginatedResponse{
+		Success:    true,
+		Message:    message,
+		Data:       data,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}, nil
+}
+
+// selectFields trims each item down to the requested fields.
+func (p pageRequest) selectFields(items []models.Property) (interface{}, error) {
+	if len(p.Fields) == 0 {
+		return items, nil
+	}
+
+	trimmed := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var full map[string]interface{}
+		if err := json.Unmarshal(raw, &full); err != nil {
+			return nil, err
+		}
+
+		selected := make(map[string]interface{}, len(p.Fields)+len(alwaysIncludedFields)+1)
+		for _, field := range slices.Concat(alwaysIncludedFields, p.Fields) {
+			if value, ok := full[field]; ok {
+				selected[field] = value
+			}
+		}
+		if item.RecommendedBy != "" {
+			selected["recommendedBy"] = item.RecommendedBy
+		}
+		if item.Score != 0 {
+			selected["score"] = item.Score
+		}
+		if item.DistanceKm != nil {
+			selected["distanceKm"] = *item.DistanceKm
+		}
+		trimmed = append(trimmed, selected)
+	}
+	return trimmed, nil
+}
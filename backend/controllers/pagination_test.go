@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	id := primitive.NewObjectID()
+	cursor := encodeCursor(pageCursor{ID: id})
+	priceDesc := []store.SortField{{Field: "price", Descending: true}}
+	sortedCursor := encodeCursor(pageCursor{Sort: "-price", Values: []interface{}{100.0}, ID: id})
+
+	tests := []struct {
+		name    string
+		query   string
+		want    pageRequest
+		wantErr string
+	}{
+		{name: "defaults", query: "", want: pageRequest{Limit: defaultPageSize}},
+		{name: "limit", query: "limit=5", want: pageRequest{Limit: 5}},
+		{name: "limit is capped", query: "limit=1000", want: pageRequest{Limit: maxPageSize}},
+		{name: "offset", query: "limit=5&offset=7", want: pageRequest{Limit: 5, Offset: 7}},
+		{name: "page", query: "limit=5&page=3", want: pageRequest{Limit: 5, Offset: 10}},
+		{name: "cursor", query: "cursor=" + cursor, want: pageRequest{Limit: defaultPageSize, Cursor: &pageCursor{ID: id}}},
+		{name: "sort", query: "sort=-price,+availableFrom", want: pageRequest{Limit: defaultPageSize,
+			Sort: []store.SortField{{Field: "price", Descending: true}, {Field: "availableFrom"}}}},
+		{name: "sorted cursor", query: "sort=-price&cursor=" + sortedCursor, want: pageRequest{Limit: defaultPageSize,
+			Sort: priceDesc, Cursor: &pageCursor{Sort: "-price", Values: []interface{}{100.0}, ID: id}}},
+		{name: "cursor for another sort", query: "sort=price&cursor=" + sortedCursor, wantErr: "invalid cursor"},
+		{name: "unsortable field", query: "sort=title", wantErr: `cannot sort by "title"`},
+		{name: "duplicate sort field", query: "sort=price,-price", wantErr: `duplicate sort field "price"`},
+		{name: "zero limit", query: "limit=0", wantErr: "limit must be a positive integer"},
+		{name: "non numeric limit", query: "limit=ten", wantErr: "limit must be a positive integer"},
+		{name: "negative offset", query: "offset=-1", wantErr: "offset must be a non-negative integer"},
+		{name: "zero page", query: "page=0", wantErr: "page must be a positive integer"},
+		{name: "page and offset", query: "page=2&offset=1", wantErr: "page and offset cannot be combined"},
+		{name: "cursor and offset", query: "offset=1&cursor=" + cursor, wantErr: "cursor cannot be combined with page or offset"},
+		{name: "garbage cursor", query: "cursor=!!", wantErr: "invalid cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parsePageRequest(query)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parsePageRequest(%q) error = %v, want %q", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePageRequest(%q) error: %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePageRequest(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	id := primitive.NewObjectID()
+	available := time.Date(2024, 3, 1, 10, 30, 0, 500, time.UTC)
+	sortFields := []store.SortField{{Field: "price", Descending: true}, {Field: "availableFrom"}}
+	const sort = "-price,availableFrom"
+
+	tests := []struct {
+		name    string
+		encoded string
+		sort    []store.SortField
+		want    pageCursor
+		wantErr bool
+	}{
+		{name: "round trip", encoded: encodeCursor(pageCursor{ID: id}), want: pageCursor{ID: id}},
+		{name: "sort values are restored", sort: sortFields,
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{1500.0, available.Format(time.RFC3339Nano)}, ID: id}),
+			want:    pageCursor{Sort: sort, Values: []interface{}{1500.0, available}, ID: id}},
+		{name: "null sort values", sort: sortFields,
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{nil, nil}, ID: id}),
+			want:    pageCursor{Sort: sort, Values: []interface{}{nil, nil}, ID: id}},
+		{name: "not base64", encoded: "%%%", wantErr: true},
+		{name: "not json", encoded: "bm90LWpzb24", wantErr: true},
+		{name: "no position", encoded: encodeCursor(pageCursor{}), wantErr: true},
+		{name: "different sort", sort: sortFields[:1],
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{1500.0, nil}, ID: id}), wantErr: true},
+		{name: "missing values", sort: sortFields,
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{1500.0}, ID: id}), wantErr: true},
+		{name: "date for numeric field", sort: sortFields,
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{"2024-03-01T00:00:00Z", nil}, ID: id}), wantErr: true},
+		{name: "number for date field", sort: sortFields,
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{1500.0, 1.0}, ID: id}), wantErr: true},
+		{name: "unexpected type", sort: sortFields,
+			encoded: encodeCursor(pageCursor{Sort: sort, Values: []interface{}{true, nil}, ID: id}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.encoded, tt.sort)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeCursor(%q) = %+v, want error", tt.encoded, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCursor(%q) error: %v", tt.encoded, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCursor(%q) = %+v, want %+v", tt.encoded, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	after := bson.M{"$or": bson.A{bson.M{"_id": bson.M{"$gt": id}}}}
+
+	tests := []struct {
+		name   string
+		page   pageRequest
+		filter bson.M
+		want   bson.M
+	}{
+		{name: "no cursor", page: pageRequest{}, filter: bson.M{"city": "Pune"}, want: bson.M{"city": "Pune"}},
+		{name: "cursor without filter", page: pageRequest{Cursor: &pageCursor{ID: id}}, filter: bson.M{}, want: after},
+		{name: "cursor with filter", page: pageRequest{Cursor: &pageCursor{ID: id}}, filter: bson.M{"city": "Pune"},
+			want: bson.M{"$and": bson.A{bson.M{"city": "Pune"}, after}}},
+		{name: "ascending then descending",
+			page: pageRequest{
+				Sort:   []store.SortField{{Field: "price"}, {Field: "rating", Descending: true}},
+				Cursor: &pageCursor{Values: []interface{}{100.0, 4.0}, ID: id},
+			},
+			filter: bson.M{},
+			want: bson.M{"$or": bson.A{
+				bson.M{"price": bson.M{"$gt": 100.0}},
+				bson.M{"price": 100.0, "$or": bson.A{
+					bson.M{"rating": bson.M{"$lt": 4.0}},
+					bson.M{"rating": nil},
+				}},
+				bson.M{"price": 100.0, "rating": 4.0, "_id": bson.M{"$gt": id}},
+			}}},
+		{name: "null ascending value",
+			page: pageRequest{
+				Sort:   []store.SortField{{Field: "rating"}},
+				Cursor: &pageCursor{Values: []interface{}{nil}, ID: id},
+			},
+			filter: bson.M{},
+			want: bson.M{"$or": bson.A{
+				bson.M{"rating": bson.M{"$ne": nil}},
+				bson.M{"rating": nil, "_id": bson.M{"$gt": id}},
+			}}},
+		{name: "null descending value",
+			page: pageRequest{
+				Sort:   []store.SortField{{Field: "rating", Descending: true}},
+				Cursor: &pageCursor{Values: []interface{}{nil}, ID: id},
+			},
+			filter: bson.M{},
+			want: bson.M{"$or": bson.A{
+				bson.M{"rating": nil, "_id": bson.M{"$gt": id}},
+			}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.page.keysetFilter(tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keysetFilter(%v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPage(t *testing.T) {
+	available := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := make([]models.Property, 4)
+	for i := range items {
+		items[i].ID = primitive.NewObjectID()
+		items[i].Price = 1000 * (4 - i)
+		items[i].AvailableFrom = available
+	}
+	sortFields := []store.SortField{{Field: "price", Descending: true}, {Field: "availableFrom"}}
+
+	tests := []struct {
+		name       string
+		limit      int64
+		sort       []store.SortField
+		items      []models.Property
+		wantItems  int
+		wantMore   bool
+		wantValues []interface{}
+	}{
+		{name: "empty", limit: 3, items: nil, wantItems: 0},
+		{name: "short page", limit: 3, items: items[:2], wantItems: 2},
+		{name: "exactly limit", limit: 3, items: items[:3], wantItems: 3},
+		{name: "limit plus one", limit: 3, items: items, wantItems: 3, wantMore: true},
+		{name: "limit plus one sorted", limit: 3, sort: sortFields, items: items, wantItems: 3, wantMore: true,
+			wantValues: []interface{}{2000.0, available}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pageRequest{Limit: tt.limit, Sort: tt.sort}
+			if got := p.findOptions(); !reflect.DeepEqual(got, store.FindOptions{Limit: tt.limit + 1, Sort: tt.sort}) {
+				t.Errorf("findOptions() = %+v, want limit %d", got, tt.limit+1)
+			}
+
+			page := p.buildPage(tt.items, 42)
+			if page.Items == nil || len(page.Items) != tt.wantItems {
+				t.Fatalf("buildPage items = %v, want %d items", page.Items, tt.wantItems)
+			}
+			if page.Total != 42 || page.HasMore != tt.wantMore {
+				t.Errorf("buildPage total, hasMore = %d, %v; want 42, %v", page.Total, page.HasMore, tt.wantMore)
+			}
+			if !tt.wantMore {
+				if page.NextCursor != "" {
+					t.Errorf("buildPage nextCursor = %q, want none", page.NextCursor)
+				}
+				return
+			}
+			cursor, err := decodeCursor(page.NextCursor, tt.sort)
+			if err != nil {
+				t.Fatalf("decodeCursor(nextCursor) error: %v", err)
+			}
+			if last := page.Items[len(page.Items)-1].ID; cursor.ID != last {
+				t.Errorf("nextCursor points at %s, want last returned item %s", cursor.ID.Hex(), last.Hex())
+			}
+			if !reflect.DeepEqual(cursor.Values, tt.wantValues) {
+				t.Errorf("nextCursor values = %v, want %v", cursor.Values, tt.wantValues)
+			}
+		})
+	}
+}
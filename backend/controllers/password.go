@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/mail"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+	"github.com/dcode-github/property_lisitng_system/backend/validation"
+)
+
+// forgotPasswordMessage is returned whether or not the email belongs to an
+// account, so the endpoint cannot be used to discover registered addresses.
+const forgotPasswordMessage = "If an account exists for that email, a password reset token has been sent to it"
+
+const (
+	passwordResetQueueSize = 64
+	passwordResetTimeout   = 30 * time.Second
+)
+
+// ForgotPassword mails a single-use password reset token to the account
+// registered with the given email. Requesting a new token invalidates the
+// previous ones. The lookup and mail happen on a background worker after the
+// response, so its timing does not reveal whether the account exists.
+func ForgotPassword(users store.UserStore, tokens store.OneTimeTokenStore, mailer mail.Mailer, lifetimes utils.TokenLifetimes) http.HandlerFunc {
+	queue := make(chan string, passwordResetQueueSize)
+	go func() {
+		for email := range queue {
+			ctx, cancel := context.WithTimeout(context.Background(), passwordResetTimeout)
+			if err := sendPasswordReset(ctx, users, tokens, mailer, lifetimes, email); err != nil {
+				log.Printf("Failed to send password reset for %s: %v", email, err)
+			}
+			cancel()
+		}
+	}()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			log.Printf("Invalid request body for ForgotPassword: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if body.Email == "" {
+			writeValidationError(w, "ForgotPassword", validation.Errors{{Field: "email", Reason: "is required"}})
+			return
+		}
+
+		// The response is the same even when the request cannot be queued.
+		select {
+		case queue <- body.Email:
+		default:
+			log.Printf("Password reset queue is full, dropping request for %s", body.Email)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(Response{Message: forgotPasswordMessage})
+	}
+}
+
+func sendPasswordReset(ctx context.Context, users store.UserStore, tokens store.OneTimeTokenStore, mailer mail.Mailer, lifetimes utils.TokenLifetimes, email string) error {
+	user, err := users.FindByEmail(ctx, email)
+	if err == store.ErrNotFound {
+		log.Printf("Password reset requested for unknown email %s", email)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := issueOneTimeToken(ctx, tokens, user.UserID, models.TokenPurposePasswordReset, lifetimes.PasswordReset)
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("A password reset was requested for the account %s.\n\n"+
+			"Reset token: %s\n\n"+
+			"The token can be used once and expires in %s. If you did not request a reset, ignore this email.",
+			user.UserID, token, lifetimes.PasswordReset),
+	})
+}
+
+// ResetPassword sets a new password using a token mailed by ForgotPassword
+// and logs the account out of every session.
+func ResetPassword(users store.UserStore, tokens store.OneTimeTokenStore, refreshTokens store.RefreshTokenStore, revocations store.RevocationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		var body struct {
+			Token    string `json:"token"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			log.Printf("Invalid request body for ResetPassword: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		var errs validation.Errors
+		if body.Token == "" {
+			errs.Add("token", "is required")
+		}
+		if body.Password == "" {
+			errs.Add("password", "is required")
+		}
+		if len(errs) > 0 {
+			writeValidationError(w, "ResetPassword", errs)
+			return
+		}
+
+		// The password is hashed before the token is consumed so a hashing
+		// failure does not burn the token.
+		hashedPwd, err := utils.HashPassword(body.Password)
+		if err != nil {
+			log.Printf("Error hashing password: %v", err)
+			http.Error(w, "Failed to hash password", http.StatusInternalServerError)
+			return
+		}
+
+		token, err := tokens.Consume(requestCtx, utils.HashToken(body.Token), models.TokenPurposePasswordReset)
+		if err == store.ErrNotFound {
+			http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to consume password reset token: %v", err)
+			http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+			return
+		}
+
+		found, err := users.SetPassword(requestCtx, token.UserID, hashedPwd)
+		if err != nil {
+			log.Printf("Failed to set password of user %s: %v", token.UserID, err)
+			http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+			return
+		}
+
+		if err := revokeUserSessions(requestCtx, refreshTokens, revocations, token.UserID); err != nil {
+			log.Printf("Failed to revoke sessions of user %s after password reset: %v", token.UserID, err)
+			http.Error(w, "Password was reset but existing sessions could not be revoked", http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Password of user %s was reset", token.UserID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Response{Message: "Password has been reset, please log in again"})
+	}
+}
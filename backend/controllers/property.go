@@ -4,38 +4,62 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json" // For generatePropertyDetailCacheKey (if you add it later)
+	"encoding/json"
+	"errors"
 	"log"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/dcode-github/property_lisitng_system/backend/config"
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
+	"github.com/dcode-github/property_lisitng_system/backend/filter"
 	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+	"github.com/dcode-github/property_lisitng_system/backend/validation"
 	"github.com/gorilla/mux"
 
-	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ContextKey string
 
 const UserIDKey = ContextKey("userID")
 
-const (
-	propertyListCachePrefix = "property:list:"
-	defaultCacheTTL         = 10 * time.Minute
+// ClaimsKey holds the *utils.Claims of the caller's access token.
+const ClaimsKey = ContextKey("claims")
 
-	cacheScanPatternProperty = "property:*"
-	cacheScanCount           = 100
+// callerRole returns the role in the caller's access token. Anonymous callers
+// and tokens issued before roles existed count as RoleUser.
+func callerRole(ctx context.Context) string {
+	if claims, ok := ctx.Value(ClaimsKey).(*utils.Claims); ok && claims.Role != "" {
+		return claims.Role
+	}
+	return models.RoleUser
+}
+
+const (
+	propertyListCachePrefix         = "property:list:"
+	propertyDetailCachePrefix       = "property:detail:"
+	propertyRecommendersCachePrefix = "recommendations:property:user:"
+	defaultCacheTTL                 = 10 * time.Minute
+	defaultStaleTTL                 = time.Minute
 )
 
-func CreateProperty(redisClient *redis.Client) http.HandlerFunc {
+// searchParams are handled by GetAllProperties itself rather than turned
+// into filter conditions.
+var searchParams = map[string]bool{"userID": true, "facets": true, "q": true}
+
+var propertyFilterIgnoredParams = mergeParams(searchParams, geoParams, paginationParams)
+
+var listCacheOptions = cache.LoadOptions{TTL: defaultCacheTTL, StaleTTL: defaultStaleTTL}
+
+func CreateProperty(properties store.PropertyStore, appCache cache.Cache, matcher *SavedSearchMatcher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := r.Context().Value(UserIDKey).(string)
 		if !ok {
@@ -51,249 +75,258 @@ func CreateProperty(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
+		if errs := validateProperty(&property, nil); len(errs) > 0 {
+			writeValidationError(w, "CreateProperty", errs)
+			return
+		}
+
 		objectID := primitive.NewObjectID()
 		property.ID = objectID
 		property.PropId = objectID.Hex()
 		property.CreatedBy = userID
+		property.IsVerified = false
+		// Search and per-user fields are computed per request and never
+		// taken from the client.
+		property.Score = 0
+		property.DistanceKm = nil
+		property.IsFavorite = false
+		property.RecommendedBy = ""
+		property.VerificationStatus = models.VerificationPending
+		property.VerificationHistory = []models.VerificationEvent{{
+			Status: models.VerificationPending,
+			Reason: "Listing created",
+			By:     userID,
+			At:     time.Now(),
+		}}
 		if property.AvailableFrom.IsZero() {
 			property.AvailableFrom = time.Now()
 		}
 
-		_, err := config.PropertyCollection.InsertOne(r.Context(), property)
-		if err != nil {
+		if err := properties.Create(r.Context(), &property); err != nil {
 			log.Printf("Insert failed for CreateProperty: %v", err)
 			http.Error(w, "Failed to create property", http.StatusInternalServerError)
 			return
 		}
 
 		go func() {
-			deletePropertyCache(redisClient)
+			invalidatePropertyCache(appCache)
 		}()
+		matcher.PropertyWritten(property.ID)
 
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(property)
 	}
 }
 
-func GetAllProperties(redisClient *redis.Client) http.HandlerFunc {
+func GetAllProperties(properties store.PropertyStore, favorites store.FavoriteStore, appCache cache.Cache) http.HandlerFunc {
+	loader := cache.NewLoader(appCache)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
-		userID, ok := requestCtx.Value(UserIDKey).(string)
-		if !ok {
-			log.Println("User ID missing in context for GetAllProperties")
-			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
-			return
-		}
+		// Anonymous callers are allowed and get no per-user state.
+		userID, signedIn := requestCtx.Value(UserIDKey).(string)
 
 		query := r.URL.Query()
-		cacheKey := generateCacheKeyForPropertyList(userID, query)
-
-		cachedData, err := redisClient.Get(requestCtx, cacheKey).Result()
-		if err == nil {
-			log.Printf("Cache Hit for GetAllProperties key: %s", cacheKey)
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(cachedData))
+		search, err := parsePropertySearch(query)
+		if err != nil {
+			writeQueryError(w, "GetAllProperties", err)
 			return
 		}
-		if err != redis.Nil {
-			log.Printf("Redis GET error for GetAllProperties key %s: %v", cacheKey, err)
-		}
-
-		log.Printf("Cache Miss for GetAllProperties key: %s", cacheKey)
+		page, geo, filter := search.Page, search.Geo, search.Filter
+		findOptions := search.findOptions()
 
-		var andConditions []bson.M
-		fieldSpecificConditions := make(map[string]bson.M)
+		versions, cacheable := cacheVersions(requestCtx, appCache, propertyVersionKey)
+		cacheKey := generateCacheKeyForPropertyList(query, versions)
 
-		operatorMap := map[string]string{
-			"eq": "$eq", "ne": "$ne", "gt": "$gt", "gte": "$gte", "lt": "$lt", "lte": "$lte",
-		}
-		numericFields := map[string]bool{
-			"price": true, "areaSqFt": true, "bedrooms": true, "bathrooms": true, "rating": true,
-		}
-		dateFields := map[string]bool{"availableFrom": true}
-		boolFields := map[string]bool{"isVerified": true}
-		stringFields := map[string]bool{
-			"id": true, "propId": true, "title": true, "type": true, "state": true, "city": true,
-			"furnished": true, "listedBy": true, "listingType": true, "createdBy": true,
-		}
-
-		for rawKey, queryValues := range query {
-			if rawKey == "userID" || len(queryValues) == 0 || queryValues[0] == "" {
-				continue
+		pageBytes, err := loadCached(requestCtx, loader, cacheable, cacheKey, listCacheOptions, "GetAllProperties", func(ctx context.Context) ([]byte, error) {
+			results, err := properties.Find(ctx, page.keysetFilter(filter), findOptions)
+			if err != nil {
+				log.Printf("Error fetching properties with query %+v: %v", filter, err)
+				return nil, err
 			}
+			geo.setDistances(results)
 
-			fieldKey := rawKey
-			mongoOperator := "$eq"
-
-			if strings.Contains(rawKey, "[") && strings.Contains(rawKey, "]") {
-				parts := strings.SplitN(rawKey, "[", 2)
-				fieldKey = parts[0]
-				opKey := strings.TrimSuffix(parts[1], "]")
-				if mappedOp, exists := operatorMap[opKey]; exists {
-					mongoOperator = mappedOp
-				} else {
-					log.Printf("Unknown operator key: %s in query param %s", opKey, rawKey)
-					continue
-				}
-			}
-			queryValue := queryValues[0]
-			if fieldKey == "tags" || fieldKey == "amenities" {
-				terms := strings.Split(queryValue, ",")
-				var orClausesForField bson.A
-				for _, term := range terms {
-					trimmedTerm := strings.TrimSpace(term)
-					if trimmedTerm == "" {
-						continue
-					}
-					orClausesForField = append(orClausesForField, bson.M{fieldKey: bson.M{"$regex": primitive.Regex{Pattern: trimmedTerm, Options: "i"}}})
-				}
-				if len(orClausesForField) > 0 {
-					andConditions = append(andConditions, bson.M{"$or": orClausesForField})
-				}
-				continue
+			total, err := properties.Count(ctx, filter)
+			if err != nil {
+				log.Printf("Error counting properties with query %+v: %v", filter, err)
+				return nil, err
 			}
 
-			if stringFields[fieldKey] {
-				values := strings.Split(queryValue, ",")
-				var trimmedValues []string
-				for _, v := range values {
-					trimmedV := strings.TrimSpace(v)
-					if trimmedV != "" {
-						trimmedValues = append(trimmedValues, trimmedV)
-					}
-				}
-				if len(trimmedValues) > 0 {
-					if mongoOperator == "$eq" {
-						andConditions = append(andConditions, bson.M{fieldKey: bson.M{"$in": trimmedValues}})
-					} else if mongoOperator == "$ne" {
-						andConditions = append(andConditions, bson.M{fieldKey: bson.M{"$nin": trimmedValues}})
-					} else {
-						log.Printf("Unsupported operator '%s' for string field '%s'. Defaulting to $eq/$in.", mongoOperator, fieldKey)
-						andConditions = append(andConditions, bson.M{fieldKey: bson.M{"$in": trimmedValues}})
-					}
-				}
-				continue
+			resultBytes, err := json.Marshal(page.buildPage(results, total))
+			if err != nil {
+				log.Printf("Failed to serialize properties for GetAllProperties: %v", err)
 			}
+			return resultBytes, err
+		})
+		if err != nil {
+			http.Error(w, "Error fetching properties", http.StatusInternalServerError)
+			return
+		}
 
-			if boolFields[fieldKey] {
-				boolVal, err := strconv.ParseBool(strings.ToLower(queryValue))
-				if err == nil {
-					andConditions = append(andConditions, bson.M{fieldKey: bson.M{mongoOperator: boolVal}})
-				} else {
-					log.Printf("Invalid boolean value for %s: %s", fieldKey, queryValue)
-				}
-				continue
-			}
+		var results propertyPage
+		if err := json.Unmarshal(pageBytes, &results); err != nil {
+			log.Printf("Failed to decode cached properties for GetAllProperties key %s: %v", cacheKey, err)
+			http.Error(w, "Error fetching properties", http.StatusInternalServerError)
+			return
+		}
 
-			if numericFields[fieldKey] || dateFields[fieldKey] {
-				if _, ok := fieldSpecificConditions[fieldKey]; !ok {
-					fieldSpecificConditions[fieldKey] = bson.M{}
-				}
+		if signedIn {
+			applyFavoriteOverlay(requestCtx, loader, appCache, favorites, userID, results.Items)
+		}
 
-				if numericFields[fieldKey] {
-					numVal, err := strconv.ParseFloat(queryValue, 64)
-					if err == nil {
-						fieldSpecificConditions[fieldKey][mongoOperator] = numVal
-					} else {
-						log.Printf("Invalid numeric value for %s operator %s: %s. Error: %v", fieldKey, mongoOperator, queryValue, err)
-					}
-				} else {
-					t, err := time.Parse("2006-01-02", queryValue)
-					if err == nil {
-						fieldSpecificConditions[fieldKey][mongoOperator] = t
-					} else {
-						log.Printf("Invalid date value for %s operator %s: %s. Error: %v", fieldKey, mongoOperator, queryValue, err)
-					}
-				}
-				continue
-			}
-			log.Printf("Unhandled query parameter: %s (parsed as field: %s)", rawKey, fieldKey)
+		response, err := page.response(results, "Fetched properties")
+		if err != nil {
+			log.Printf("Failed to select fields for GetAllProperties: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
 		}
 
-		for field, conditionsMap := range fieldSpecificConditions {
-			if len(conditionsMap) > 0 {
-				andConditions = append(andConditions, bson.M{field: conditionsMap})
+		if hasFacets(search.Facets) {
+			facetsKey := generateCacheKeyForPropertyFacets(query, versions)
+			facetBytes, err := loadCached(requestCtx, loader, cacheable, facetsKey, listCacheOptions, "GetAllProperties facets", func(ctx context.Context) ([]byte, error) {
+				facets, err := properties.Facets(ctx, filter, search.Facets)
+				if err != nil {
+					log.Printf("Error computing facets with query %+v: %v", filter, err)
+					return nil, err
+				}
+				return json.Marshal(facets)
+			})
+			if err != nil {
+				http.Error(w, "Error fetching properties", http.StatusInternalServerError)
+				return
 			}
+			response.Facets = json.RawMessage(facetBytes)
 		}
 
-		finalMongoQuery := bson.M{}
-		if len(andConditions) > 0 {
-			finalMongoQuery["$and"] = andConditions
+		resultBytes, err := json.Marshal(response)
+		if err != nil {
+			log.Printf("Failed to serialize properties for GetAllProperties: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
 		}
 
-		findOptions := options.Find().SetLimit(10)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resultBytes)
+	}
+}
+
+// applyFavoriteOverlay marks the user's favorites in a list that was cached
+// without any per-user state. Failures only lose the isFav flag.
+func applyFavoriteOverlay(ctx context.Context, loader *cache.Loader, appCache cache.Cache, favorites store.FavoriteStore, userID string, results []models.Property) {
+	if len(results) == 0 {
+		return
+	}
+
+	favSet, err := loadFavoriteIDSet(ctx, loader, appCache, favorites, userID)
+	if err != nil {
+		log.Printf("Error fetching favorites for user %s in GetAllProperties: %v", userID, err)
+		return
+	}
+	for i := range results {
+		results[i].IsFavorite = favSet[results[i].ID]
+	}
+}
+
+func GetPropertyByID(properties store.PropertyStore, favorites store.FavoriteStore, recommendations store.RecommendationStore, appCache cache.Cache) http.HandlerFunc {
+	loader := cache.NewLoader(appCache)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		// Anonymous callers are allowed and get no per-user state.
+		userID, signedIn := requestCtx.Value(UserIDKey).(string)
 
-		cursor, err := config.PropertyCollection.Find(requestCtx, finalMongoQuery, findOptions)
+		propertyID := mux.Vars(r)["id"]
+		objID, err := primitive.ObjectIDFromHex(propertyID)
 		if err != nil {
-			log.Printf("Error fetching properties with query %+v: %v", finalMongoQuery, err)
-			http.Error(w, "Error fetching properties", http.StatusInternalServerError)
+			log.Printf("Invalid property ID '%s' for GetPropertyByID: %v", propertyID, err)
+			http.Error(w, "Invalid property ID", http.StatusBadRequest)
 			return
 		}
-		defer cursor.Close(requestCtx)
 
-		var properties []models.Property
-		if err := cursor.All(requestCtx, &properties); err != nil {
-			log.Printf("Error decoding properties: %v", err)
-			http.Error(w, "Error decoding properties", http.StatusInternalServerError)
+		versions, cacheable := cacheVersions(requestCtx, appCache, propertyIDVersionKey(objID))
+		cacheKey := generatePropertyDetailCacheKey(objID, versions)
+
+		propertyBytes, err := loadCached(requestCtx, loader, cacheable, cacheKey, listCacheOptions, "GetPropertyByID", func(ctx context.Context) ([]byte, error) {
+			results, err := properties.Find(ctx, bson.M{"_id": objID}, store.FindOptions{Limit: 1})
+			if err != nil {
+				log.Printf("Error fetching property %s: %v", propertyID, err)
+				return nil, err
+			}
+			if len(results) == 0 {
+				return nil, store.ErrNotFound
+			}
+			return json.Marshal(results[0])
+		})
+		if errors.Is(err, store.ErrNotFound) {
+			http.Error(w, "Property not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Error fetching property", http.StatusInternalServerError)
 			return
 		}
 
-		if len(properties) > 0 {
-			propertyIDs := make([]primitive.ObjectID, 0, len(properties))
-			for _, prop := range properties {
-				propertyIDs = append(propertyIDs, prop.ID)
-			}
+		var property models.Property
+		if err := json.Unmarshal(propertyBytes, &property); err != nil {
+			log.Printf("Failed to decode cached property for GetPropertyByID key %s: %v", cacheKey, err)
+			http.Error(w, "Error fetching property", http.StatusInternalServerError)
+			return
+		}
 
-			favFilter := bson.M{
-				"userID":     userID,
-				"propertyID": bson.M{"$in": propertyIDs},
-			}
+		if signedIn {
+			results := []models.Property{property}
+			applyFavoriteOverlay(requestCtx, loader, appCache, favorites, userID, results)
+			property = results[0]
 
-			favCursor, err := config.FavoriteCollection.Find(requestCtx, favFilter)
+			recommendedBy, err := loadPropertyRecommender(requestCtx, loader, appCache, recommendations, userID, objID)
 			if err != nil {
-				log.Printf("Error fetching favorites for user %s in GetAllProperties: %v", userID, err)
-			} else {
-				defer favCursor.Close(requestCtx)
-				favMap := make(map[primitive.ObjectID]bool)
-				for favCursor.Next(requestCtx) {
-					var fav models.Favorite
-					if err := favCursor.Decode(&fav); err != nil {
-						log.Printf("Error decoding favorite in GetAllProperties: %v", err)
-						continue
-					}
-					favMap[fav.PropertyID] = true
-				}
-				if favCursor.Err() != nil {
-					log.Printf("Favorite cursor iteration error in GetAllProperties: %v", favCursor.Err())
-				}
-
-				for i := range properties {
-					if _, isFav := favMap[properties[i].ID]; isFav {
-						properties[i].IsFavorite = true
-					}
-				}
+				log.Printf("Error fetching recommendation for property %s and user %s in GetPropertyByID: %v", propertyID, userID, err)
 			}
+			property.RecommendedBy = recommendedBy
 		}
 
-		resultBytes, err := json.Marshal(properties)
-		if err != nil {
-			log.Printf("Failed to serialize properties for GetAllProperties: %v", err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-			return
-		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: "Fetched property",
+			Data:    property,
+		})
+	}
+}
 
-		err = redisClient.Set(requestCtx, cacheKey, resultBytes, defaultCacheTTL).Err()
+// loadPropertyRecommender returns who recommended the listing to userID, or
+// an empty string when nobody did.
+func loadPropertyRecommender(ctx context.Context, loader *cache.Loader, appCache cache.Cache, recommendations store.RecommendationStore, userID string, propertyID primitive.ObjectID) (string, error) {
+	versions, cacheable := cacheVersions(ctx, appCache, propertyIDVersionKey(propertyID), userRecommendationsVersionKey(userID))
+	cacheKey := propertyRecommendersCachePrefix + userID + ":" + strings.Join(versions, ":") + ":" + propertyID.Hex()
+
+	data, err := loadCached(ctx, loader, cacheable, cacheKey, listCacheOptions, "PropertyRecommender", func(ctx context.Context) ([]byte, error) {
+		results, err := recommendations.ListProperties(ctx, userID, bson.M{"_id": propertyID}, store.FindOptions{Limit: 1})
 		if err != nil {
-			log.Printf("Failed to cache response for GetAllProperties key %s: %v", cacheKey, err)
+			return nil, err
 		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(resultBytes)
+		var recommendedBy string
+		if len(results) > 0 {
+			recommendedBy = results[0].RecommendedBy
+		}
+		return json.Marshal(recommendedBy)
+	})
+	if err != nil {
+		return "", err
 	}
+
+	var recommendedBy string
+	err = json.Unmarshal(data, &recommendedBy)
+	return recommendedBy, err
+}
+
+func buildPropertyFilter(query url.Values) (bson.M, error) {
+	return filter.Build(query, propertyFilterIgnoredParams, filter.DefaultLimits)
 }
 
-func UpdateProperty(redisClient *redis.Client) http.HandlerFunc {
+func UpdateProperty(properties store.PropertyStore, appCache cache.Cache, matcher *SavedSearchMatcher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -312,53 +345,79 @@ func UpdateProperty(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
-		var updateData map[string]interface{}
+		var updateData map[string]json.RawMessage
 		if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
 			log.Printf("Invalid update data for UpdateProperty: %v", err)
 			http.Error(w, "Invalid update data", http.StatusBadRequest)
 			return
 		}
 
-		delete(updateData, "_id")
-		delete(updateData, "id")
-		delete(updateData, "propId")
-		delete(updateData, "createdBy")
+		if len(updateData) == 0 {
+			http.Error(w, "No fields to update", http.StatusBadRequest)
+			return
+		}
 
-		if af, ok := updateData["availableFrom"].(string); ok {
-			t, err := time.Parse(time.RFC3339, af)
-			if err == nil {
-				updateData["availableFrom"] = t
-			} else {
-				log.Printf("Could not parse 'availableFrom' string '%s' as RFC3339 time for UpdateProperty: %v", af, err)
+		var errs validation.Errors
+		fields := slices.Sorted(maps.Keys(updateData))
+		for _, field := range fields {
+			if !propertyUpdatableFields[field] {
+				errs.Add(field, "cannot be updated")
 			}
 		}
 
-		filter := bson.M{"_id": objID, "createdBy": userID}
-		update := bson.M{"$set": updateData}
+		owner := store.OwnedBy(userID)
+		if callerRole(requestCtx) == models.RoleAdmin {
+			owner = store.AnyOwner
+		}
+
+		var update models.Property
+		if len(errs) == 0 {
+			errs = validation.DecodeFields(updateData, &update)
+		}
+		if len(errs) == 0 {
+			errs = validateProperty(&update, fields)
+		}
+		if len(errs) > 0 {
+			writeValidationError(w, "UpdateProperty", errs)
+			return
+		}
+
+		doc, err := toBSONDocument(update)
+		if err != nil {
+			log.Printf("Failed to encode update for property %s in UpdateProperty: %v", propertyID, err)
+			http.Error(w, "Update failed", http.StatusInternalServerError)
+			return
+		}
+		setFields := bson.M{}
+		for _, field := range fields {
+			setFields[field] = doc[field]
+		}
 
-		res, err := config.PropertyCollection.UpdateOne(requestCtx, filter, update)
+		matched, err := properties.Update(requestCtx, objID, owner, setFields, verificationReset(userID, fields))
 		if err != nil {
 			log.Printf("Update failed for property %s in UpdateProperty: %v", propertyID, err)
 			http.Error(w, "Update failed", http.StatusInternalServerError)
 			return
 		}
 
-		if res.MatchedCount == 0 {
+		if !matched {
 			log.Printf("No property found with ID %s and createdBy %s for UpdateProperty, or unauthorized.", propertyID, userID)
 			http.Error(w, "No property found or unauthorized", http.StatusForbidden)
 			return
 		}
 
 		go func() {
-			deletePropertyCache(redisClient)
+			invalidatePropertyCache(appCache)
+			invalidatePropertyDetailCache(context.Background(), appCache, objID)
 		}()
+		matcher.PropertyWritten(objID)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]string{"message": "Property updated successfully"})
 	}
 }
 
-func DeleteProperty(redisClient *redis.Client) http.HandlerFunc {
+func DeleteProperty(properties store.PropertyStore, favorites store.FavoriteStore, recommendations store.RecommendationStore, appCache cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -377,38 +436,39 @@ func DeleteProperty(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
-		filter := bson.M{"_id": objID, "createdBy": userID}
-		propertyDeleteResult, err := config.PropertyCollection.DeleteOne(requestCtx, filter)
+		owner := store.OwnedBy(userID)
+		if callerRole(requestCtx) == models.RoleAdmin {
+			owner = store.AnyOwner
+		}
+
+		deleted, err := properties.Delete(requestCtx, objID, owner)
 		if err != nil {
-			log.Printf("Delete from PropertyCollection failed for property %s in DeleteProperty: %v", propertyID, err)
+			log.Printf("Delete failed for property %s in DeleteProperty: %v", propertyID, err)
 			http.Error(w, "Delete failed", http.StatusInternalServerError)
 			return
 		}
 
-		if propertyDeleteResult.DeletedCount == 0 {
+		if !deleted {
 			log.Printf("No property found with ID %s and createdBy %s for DeleteProperty, or unauthorized.", propertyID, userID)
 			http.Error(w, "No property found or unauthorized to delete", http.StatusForbidden)
 			return
 		}
 
-		recommendationFilter := bson.M{"propertyID": objID}
-		_, err = config.RecommendationCollection.DeleteMany(requestCtx, recommendationFilter)
-		if err != nil {
+		if err := recommendations.DeleteByProperty(requestCtx, objID); err != nil {
 			log.Printf("Warning: Failed to delete recommendations for property %s in DeleteProperty: %v", propertyID, err)
 		} else {
 			log.Printf("Successfully deleted recommendations associated with property %s.", propertyID)
 		}
 
-		favoriteFilter := bson.M{"propertyID": objID}
-		_, err = config.FavoriteCollection.DeleteMany(requestCtx, favoriteFilter)
-		if err != nil {
+		if err := favorites.DeleteByProperty(requestCtx, objID); err != nil {
 			log.Printf("Warning: Failed to delete favorites for property %s in DeleteProperty: %v", propertyID, err)
 		} else {
 			log.Printf("Successfully deleted favorites associated with property %s.", propertyID)
 		}
 
 		go func() {
-			deletePropertyCache(redisClient)
+			invalidatePropertyCache(appCache)
+			invalidatePropertyDetailCache(context.Background(), appCache, objID)
 		}()
 
 		w.Header().Set("Content-Type", "application/json")
@@ -416,19 +476,29 @@ func DeleteProperty(redisClient *redis.Client) http.HandlerFunc {
 	}
 }
 
-func generateCacheKeyForPropertyList(userID string, queryParams url.Values) string {
+func generateCacheKeyForPropertyList(queryParams url.Values, versions []string) string {
+	return propertyListCachePrefix + strings.Join(versions, ":") + ":" + hashQuery(queryParams)
+}
+
+func generatePropertyDetailCacheKey(propertyID primitive.ObjectID, versions []string) string {
+	return propertyDetailCachePrefix + propertyID.Hex() + ":" + strings.Join(versions, ":")
+}
+
+// hashQuery keys a query on every value in the order it was sent. Values are
+// not sorted: the parsers read the first value of a parameter, so
+// city=A&city=B and city=B&city=A may produce different responses.
+func hashQuery(queryParams url.Values) string {
 	keys := make([]string, 0, len(queryParams))
 	for k := range queryParams {
+		if k == "userID" {
+			continue
+		}
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	var sb strings.Builder
-	sb.WriteString(userID)
-	sb.WriteString(":")
 	for _, key := range keys {
-		values := queryParams[key]
-		sort.Strings(values)
-		for _, val := range values {
+		for _, val := range queryParams[key] {
 			sb.WriteString(key)
 			sb.WriteString("=")
 			sb.WriteString(val)
@@ -438,45 +508,17 @@ func generateCacheKeyForPropertyList(userID string, queryParams url.Values) stri
 	rawKeyComponent := strings.TrimSuffix(sb.String(), "&")
 
 	sum := sha256.Sum256([]byte(rawKeyComponent))
-	return propertyListCachePrefix + hex.EncodeToString(sum[:])
+	return hex.EncodeToString(sum[:])
 }
 
-func deletePropertyCache(redisClient *redis.Client) {
-	ctx := context.Background()
-
-	var keysToDelete []string
-	var cursor uint64
-	var iterErr error
-
-	log.Printf("Starting blanket property cache invalidation (pattern: %s)...", cacheScanPatternProperty)
-
-	for {
-		var currentKeys []string
-		currentKeys, cursor, iterErr = redisClient.Scan(ctx, cursor, cacheScanPatternProperty, cacheScanCount).Result()
-		if iterErr != nil {
-			log.Printf("Error during Redis SCAN for pattern '%s': %v", cacheScanPatternProperty, iterErr)
-			return
-		}
-		keysToDelete = append(keysToDelete, currentKeys...)
-		if cursor == 0 {
-			break
-		}
-	}
-
-	if len(keysToDelete) == 0 {
-		log.Printf("No property cache keys found matching pattern '%s' to delete.", cacheScanPatternProperty)
-		return
-	}
-
-	pipe := redisClient.Pipeline()
-	for _, key := range keysToDelete {
-		pipe.Del(ctx, key)
-	}
-	_, execErr := pipe.Exec(ctx)
+// invalidatePropertyCache moves every property list, favorites list and
+// recommendations list to a new generation after a listing changes.
+func invalidatePropertyCache(appCache cache.Cache) {
+	bumpCacheVersion(context.Background(), appCache, propertyVersionKey)
+}
 
-	if execErr != nil {
-		log.Printf("Error executing pipeline for deleting %d property cache keys: %v", len(keysToDelete), execErr)
-	} else {
-		log.Printf("Property Cache Invalidated. Successfully deleted %d keys matching '%s'.", len(keysToDelete), cacheScanPatternProperty)
-	}
+// invalidatePropertyDetailCache drops the detail and recommendation context
+// entries of a single listing.
+func invalidatePropertyDetailCache(ctx context.Context, appCache cache.Cache, propertyID primitive.ObjectID) {
+	bumpCacheVersion(ctx, appCache, propertyIDVersionKey(propertyID))
 }
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
+)
+
+func TestHashQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		wantSame bool
+	}{
+		{name: "parameter order is ignored", a: "city=Pune&limit=5", b: "limit=5&city=Pune", wantSame: true},
+		{name: "userID is ignored", a: "city=Pune&userID=u1", b: "city=Pune", wantSame: true},
+		{name: "different values", a: "city=Pune", b: "city=Delhi"},
+		{name: "order of repeated values matters", a: "city=A&city=B", b: "city=B&city=A"},
+		{name: "repeated sort", a: "sort=price&sort=-price", b: "sort=-price&sort=price"},
+		{name: "extra repeated value", a: "limit=5", b: "limit=5&limit=10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := url.ParseQuery(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := url.ParseQuery(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if same := hashQuery(a) == hashQuery(b); same != tt.wantSame {
+				t.Errorf("hashQuery(%q) == hashQuery(%q) is %v, want %v", tt.a, tt.b, same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestVersionKeyTTLOutlivesEntries(t *testing.T) {
+	for name, opts := range map[string]cache.LoadOptions{
+		"list":      listCacheOptions,
+		"favorites": favoriteCacheOptions,
+	} {
+		if entryTTL := opts.TTL + opts.StaleTTL; versionKeyTTL < entryTTL {
+			t.Errorf("versionKeyTTL %v is shorter than %s entries (%v)", versionKeyTTL, name, entryTTL)
+		}
+	}
+}
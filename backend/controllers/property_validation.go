@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/validation"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// propertyUpdatableFields are the JSON fields UpdateProperty accepts. Their
+// JSON and BSON names are the same.
+var propertyUpdatableFields = map[string]bool{
+	"title":         true,
+	"type":          true,
+	"price":         true,
+	"state":         true,
+	"city":          true,
+	"areaSqFt":      true,
+	"bedrooms":      true,
+	"bathrooms":     true,
+	"amenities":     true,
+	"furnished":     true,
+	"availableFrom": true,
+	"listedBy":      true,
+	"tags":          true,
+	"colorTheme":    true,
+	"rating":        true,
+	"listingType":   true,
+	"location":      true,
+}
+
+// validateProperty normalizes the terms of property and checks the given
+// fields, or all of them when fields is nil, against the model rules, the
+// term vocabularies and the location format.
+func validateProperty(property *models.Property, fields []string) validation.Errors {
+	property.Amenities = models.NewTermList(property.Amenities)
+	property.Tags = models.NewTermList(property.Tags)
+
+	var errs validation.Errors
+	if fields == nil {
+		errs = validation.Struct(property)
+		fields = []string{"amenities", "tags", "location"}
+	} else {
+		errs = validation.Fields(property, fields)
+	}
+
+	for _, field := range fields {
+		var reason string
+		switch field {
+		case "amenities":
+			reason = unknownTerms(field, property.Amenities)
+		case "tags":
+			reason = unknownTerms(field, property.Tags)
+		case "location":
+			if property.Location != nil && !property.Location.Valid() {
+				reason = "must be a GeoJSON Point with [lng, lat] coordinates"
+			}
+		}
+		if reason != "" {
+			errs.Add(field, reason)
+		}
+	}
+	return errs
+}
+
+// toBSONDocument encodes v with the field names MongoDB stores.
+func toBSONDocument(v interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	err = bson.Unmarshal(raw, &doc)
+	return doc, err
+}
+
+func writeValidationError(w http.ResponseWriter, handlerName string, errs validation.Errors) {
+	log.Printf("Validation failed for %s: %v", handlerName, errs)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(models.APIResponse{
+		Success: false,
+		Message: "Validation failed",
+		Data:    errs,
+	})
+}
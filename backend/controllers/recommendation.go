@@ -5,33 +5,27 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
-	"github.com/dcode-github/property_lisitng_system/backend/config"
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
 	"github.com/dcode-github/property_lisitng_system/backend/models"
-	"github.com/redis/go-redis/v9"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
 )
 
 const (
 	userRecommendationsCachePrefix = "recommendations:user:"
 )
 
-func generateUserRecommendationsCacheKey(userID string) string {
-	return userRecommendationsCachePrefix + userID
+func generateUserRecommendationsCacheKey(userID string, versions []string, query url.Values) string {
+	return userRecommendationsCachePrefix + userID + ":" + strings.Join(versions, ":") + ":" + hashQuery(query)
 }
 
-func deleteUserRecommendationsCache(ctx context.Context, redisClient *redis.Client, userID string) {
-	cacheKey := generateUserRecommendationsCacheKey(userID)
-	err := redisClient.Del(ctx, cacheKey).Err()
-	if err != nil && err != redis.Nil {
-		log.Printf("Error deleting user recommendations cache key %s: %v", cacheKey, err)
-	} else if err == nil {
-		log.Printf("Successfully deleted user recommendations cache key: %s", cacheKey)
-	}
+func invalidateUserRecommendationsCache(ctx context.Context, appCache cache.Cache, userID string) {
+	bumpCacheVersion(ctx, appCache, userRecommendationsVersionKey(userID))
 }
 
-func RecommendProperty(redisClient *redis.Client) http.HandlerFunc {
+func RecommendProperty(users store.UserStore, recommendations store.RecommendationStore, appCache cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -60,10 +54,9 @@ func RecommendProperty(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
-		var toUser models.User
-		err := config.UserCollection.FindOne(requestCtx, bson.M{"email": recInput.ToEmailID}).Decode(&toUser)
+		toUser, err := users.FindByEmail(requestCtx, recInput.ToEmailID)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
+			if err == store.ErrNotFound {
 				log.Printf("No such user with email %s for recommendation", recInput.ToEmailID)
 				http.Error(w, "User to recommend to not found", http.StatusBadRequest) // More specific error
 			} else {
@@ -72,23 +65,27 @@ func RecommendProperty(redisClient *redis.Client) http.HandlerFunc {
 			}
 			return
 		}
+		if !toUser.Verified {
+			log.Printf("Recommendation to unverified user %s rejected", toUser.UserID)
+			http.Error(w, "User to recommend to has not verified their email", http.StatusBadRequest)
+			return
+		}
 
 		recommendationToSave := models.Recommendation{
 			FromUserID: fromUserID,
 			ToUserID:   toUser.UserID,
-			ToEmailID:  recInput.ToEmailID,
+			ToEmailID:  toUser.Email,
 			PropertyID: recInput.PropertyID,
 		}
 
-		_, err = config.RecommendationCollection.InsertOne(requestCtx, recommendationToSave)
-		if err != nil {
+		if err := recommendations.Create(requestCtx, &recommendationToSave); err != nil {
 			log.Printf("Insert failed for recommendation from %s to %s (email %s): %v", fromUserID, toUser.UserID, recInput.ToEmailID, err)
 			http.Error(w, "Failed to send recommendation", http.StatusInternalServerError)
 			return
 		}
 
 		go func() {
-			deleteUserRecommendationsCache(context.Background(), redisClient, toUser.UserID)
+			invalidateUserRecommendationsCache(context.Background(), appCache, toUser.UserID)
 			log.Printf("Recommendation cache invalidated for recipient user %s", toUser.UserID)
 		}()
 
@@ -97,7 +94,9 @@ func RecommendProperty(redisClient *redis.Client) http.HandlerFunc {
 	}
 }
 
-func GetRecommendations(redisClient *redis.Client) http.HandlerFunc {
+func GetRecommendations(recommendations store.RecommendationStore, appCache cache.Cache) http.HandlerFunc {
+	loader := cache.NewLoader(appCache)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestCtx := r.Context()
 
@@ -108,72 +107,43 @@ func GetRecommendations(redisClient *redis.Client) http.HandlerFunc {
 			return
 		}
 
-		cacheKey := generateUserRecommendationsCacheKey(toUserID)
-		cachedData, err := redisClient.Get(requestCtx, cacheKey).Result()
-
-		if err == nil {
-			log.Printf("Cache Hit for GetRecommendations, user %s, key %s", toUserID, cacheKey)
-			w.Header().Set("Content-Type", "application/json")
-			w.Write([]byte(cachedData))
-			return
-		}
-		if err != redis.Nil {
-			log.Printf("Redis GET error for GetRecommendations user %s, key %s: %v. Fetching from DB.", toUserID, cacheKey, err)
-		}
-
-		log.Printf("Cache Miss for GetRecommendations, user %s, key %s", toUserID, cacheKey)
-
-		pipeline := mongo.Pipeline{
-			{{Key: "$match", Value: bson.M{"toUserID": toUserID}}},
-			{{Key: "$lookup", Value: bson.M{
-				"from":         config.PropertyCollection.Name(),
-				"localField":   "propertyId",
-				"foreignField": "_id",
-				"as":           "propertyDetails",
-			}}},
-			{{Key: "$unwind", Value: "$propertyDetails"}},
-			{{Key: "$replaceWith", Value: bson.M{
-				"$mergeObjects": bson.A{
-					"$propertyDetails",
-					bson.M{
-						"recommendedBy": "$fromUserId",
-					},
-				},
-			}}},
-		}
-
-		cursor, err := config.RecommendationCollection.Aggregate(requestCtx, pipeline)
+		query := r.URL.Query()
+		page, listFilter, err := parseListRequest(query)
 		if err != nil {
-			log.Printf("Error aggregating recommendations for user %s: %v", toUserID, err)
-			http.Error(w, "Failed to retrieve recommendations", http.StatusInternalServerError)
+			writeQueryError(w, "GetRecommendations", err)
 			return
 		}
-		defer cursor.Close(requestCtx)
 
-		var recommendedPropertiesWithMeta []map[string]interface{}
+		versions, cacheable := cacheVersions(requestCtx, appCache, propertyVersionKey, userRecommendationsVersionKey(toUserID))
+		cacheKey := generateUserRecommendationsCacheKey(toUserID, versions, query)
 
-		if err := cursor.All(requestCtx, &recommendedPropertiesWithMeta); err != nil {
-			log.Printf("Error decoding aggregated recommendations for user %s: %v", toUserID, err)
-			http.Error(w, "Failed to decode recommendations", http.StatusInternalServerError)
-			return
-		}
+		responseBytes, err := loadCached(requestCtx, loader, cacheable, cacheKey, listCacheOptions, "GetRecommendations", func(ctx context.Context) ([]byte, error) {
+			results, err := recommendations.ListProperties(ctx, toUserID, page.keysetFilter(listFilter), page.findOptions())
+			if err != nil {
+				log.Printf("Failed to fetch recommendations for user %s: %v", toUserID, err)
+				return nil, err
+			}
 
-		response := models.APIResponse{
-			Success: true,
-			Message: "Fetched recommended properties",
-			Data:    recommendedPropertiesWithMeta,
-		}
+			total, err := recommendations.CountProperties(ctx, toUserID, listFilter)
+			if err != nil {
+				log.Printf("Failed to count recommendations for user %s: %v", toUserID, err)
+				return nil, err
+			}
 
-		responseBytes, err := json.Marshal(response)
-		if err != nil {
-			log.Printf("Failed to marshal GetRecommendations response for user %s: %v", toUserID, err)
-			http.Error(w, "Error processing response data", http.StatusInternalServerError)
-			return
-		}
+			response, err := page.response(page.buildPage(results, total), "Fetched recommended properties")
+			if err != nil {
+				return nil, err
+			}
 
-		err = redisClient.Set(requestCtx, cacheKey, responseBytes, defaultCacheTTL).Err()
+			responseBytes, err := json.Marshal(response)
+			if err != nil {
+				log.Printf("Failed to marshal GetRecommendations response for user %s: %v", toUserID, err)
+			}
+			return responseBytes, err
+		})
 		if err != nil {
-			log.Printf("Failed to cache GetRecommendations response for user %s, key %s: %v", toUserID, cacheKey, err)
+			http.Error(w, "Failed to retrieve recommendations", http.StatusInternalServerError)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
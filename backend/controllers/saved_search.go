@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxSavedSearchesPerUser  = 20
+	maxSavedSearchNameLength = 100
+	defaultNotificationLimit = 50
+	matchTimeout             = 30 * time.Second
+	matchWorkers             = 2
+	matchQueueSize           = 256
+)
+
+// savedSearchQuery keeps only the parameters that select listings, in a
+// canonical order, and checks that they form a valid search.
+func savedSearchQuery(raw string) (string, error) {
+	query, err := url.ParseQuery(strings.TrimPrefix(raw, "?"))
+	if err != nil {
+		return "", fmt.Errorf("query is not a valid query string")
+	}
+	for key := range query {
+		if paginationParams[key] || key == "facets" || key == "userID" {
+			query.Del(key)
+		}
+	}
+	if len(query) == 0 {
+		return "", fmt.Errorf("query has no filters")
+	}
+	if _, err := parsePropertySearch(query); err != nil {
+		return "", err
+	}
+	return query.Encode(), nil
+}
+
+// SavedSearchMatcher records notifications for saved searches matching a
+// listing that was just created or updated. Matching runs on a fixed number
+// of workers fed by a bounded queue.
+type SavedSearchMatcher struct {
+	properties    store.PropertyStore
+	searches      store.SavedSearchStore
+	notifications store.NotificationStore
+	queue         chan primitive.ObjectID
+}
+
+func NewSavedSearchMatcher(properties store.PropertyStore, searches store.SavedSearchStore, notifications store.NotificationStore) *SavedSearchMatcher {
+	m := &SavedSearchMatcher{
+		properties:    properties,
+		searches:      searches,
+		notifications: notifications,
+		queue:         make(chan primitive.ObjectID, matchQueueSize),
+	}
+	for i := 0; i < matchWorkers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// PropertyWritten queues the listing to be matched against every saved
+// search. Each search notifies its owner at most once per listing. When the
+// queue is full the listing is skipped rather than holding up the request.
+func (m *SavedSearchMatcher) PropertyWritten(propertyID primitive.ObjectID) {
+	select {
+	case m.queue <- propertyID:
+	default:
+		log.Printf("Saved search queue is full, skipping matching for property %s", propertyID.Hex())
+	}
+}
+
+func (m *SavedSearchMatcher) work() {
+	for propertyID := range m.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), matchTimeout)
+		if err := m.match(ctx, propertyID); err != nil {
+			log.Printf("Saved search matching failed for property %s: %v", propertyID.Hex(), err)
+		}
+		cancel()
+	}
+}
+
+func (m *SavedSearchMatcher) match(ctx context.Context, propertyID primitive.ObjectID) error {
+	found, err := m.properties.Find(ctx, bson.M{"_id": propertyID}, store.FindOptions{Limit: 1})
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	property := found[0]
+
+	return m.searches.ForEach(ctx, func(search models.SavedSearch) error {
+		if search.UserID == property.CreatedBy {
+			return nil
+		}
+
+		query, err := url.ParseQuery(search.Query)
+		if err != nil {
+			log.Printf("Skipping saved search %s with unreadable query: %v", search.ID.Hex(), err)
+			return nil
+		}
+		parsed, err := parsePropertySearch(query)
+		if err != nil {
+			log.Printf("Skipping saved search %s that is no longer valid: %v", search.ID.Hex(), err)
+			return nil
+		}
+		// Ask the store so the search is evaluated exactly as a listing query
+		// would be.
+		matched, err := m.properties.Count(ctx, bson.M{"$and": bson.A{bson.M{"_id": property.ID}, parsed.Filter}})
+		if err != nil {
+			log.Printf("Failed to evaluate saved search %s: %v", search.ID.Hex(), err)
+			return nil
+		}
+		if matched == 0 {
+			return nil
+		}
+
+		created, err := m.notifications.Create(ctx, &models.Notification{
+			UserID:        search.UserID,
+			SavedSearchID: search.ID,
+			PropertyID:    property.ID,
+			Message:       fmt.Sprintf("New listing for your saved search %q: %s", search.Name, property.Title),
+			CreatedAt:     time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if created {
+			log.Printf("Notified user %s of property %s for saved search %s", search.UserID, property.ID.Hex(), search.ID.Hex())
+		}
+		return nil
+	})
+}
+
+func CreateSavedSearch(searches store.SavedSearchStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for CreateSavedSearch")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		var search models.SavedSearch
+		if err := json.NewDecoder(r.Body).Decode(&search); err != nil {
+			log.Printf("Invalid request body for CreateSavedSearch: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		search.Name = strings.TrimSpace(search.Name)
+		if search.Name == "" || len(search.Name) > maxSavedSearchNameLength {
+			http.Error(w, fmt.Sprintf("name is required and must be at most %d characters", maxSavedSearchNameLength), http.StatusBadRequest)
+			return
+		}
+		query, err := savedSearchQuery(search.Query)
+		if err != nil {
+			writeQueryError(w, "CreateSavedSearch", err)
+			return
+		}
+
+		existing, err := searches.ListByUser(requestCtx, userID)
+		if err != nil {
+			log.Printf("Failed to list saved searches for user %s: %v", userID, err)
+			http.Error(w, "Failed to save search", http.StatusInternalServerError)
+			return
+		}
+		if len(existing) >= maxSavedSearchesPerUser {
+			http.Error(w, fmt.Sprintf("At most %d saved searches are allowed", maxSavedSearchesPerUser), http.StatusConflict)
+			return
+		}
+
+		search.ID = primitive.NilObjectID
+		search.UserID = userID
+		search.Query = query
+		search.CreatedAt = time.Now()
+		if err := searches.Create(requestCtx, &search); err != nil {
+			log.Printf("Failed to save search for user %s: %v", userID, err)
+			http.Error(w, "Failed to save search", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(search)
+	}
+}
+
+func GetSavedSearches(searches store.SavedSearchStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for GetSavedSearches")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		results, err := searches.ListByUser(requestCtx, userID)
+		if err != nil {
+			log.Printf("Failed to list saved searches for user %s: %v", userID, err)
+			http.Error(w, "Failed to fetch saved searches", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: "Fetched saved searches",
+			Data:    results,
+		})
+	}
+}
+
+func DeleteSavedSearch(searches store.SavedSearchStore, notifications store.NotificationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for DeleteSavedSearch")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		searchID := mux.Vars(r)["id"]
+		objID, err := primitive.ObjectIDFromHex(searchID)
+		if err != nil {
+			log.Printf("Invalid saved search ID '%s' for DeleteSavedSearch: %v", searchID, err)
+			http.Error(w, "Invalid saved search ID", http.StatusBadRequest)
+			return
+		}
+
+		deleted, err := searches.Delete(requestCtx, objID, userID)
+		if err != nil {
+			log.Printf("Failed to delete saved search %s: %v", searchID, err)
+			http.Error(w, "Failed to delete saved search", http.StatusInternalServerError)
+			return
+		}
+		if !deleted {
+			http.Error(w, "Saved search not found", http.StatusNotFound)
+			return
+		}
+
+		if err := notifications.DeleteBySavedSearch(requestCtx, objID); err != nil {
+			log.Printf("Failed to delete notifications of saved search %s: %v", searchID, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Saved search deleted successfully"})
+	}
+}
+
+func GetNotifications(notifications store.NotificationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for GetNotifications")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		limit := int64(defaultNotificationLimit)
+		if v := r.URL.Query().Get("limit"); v != "" {
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || parsed < 1 {
+				writeQueryError(w, "GetNotifications", fmt.Errorf("limit must be a positive integer"))
+				return
+			}
+			limit = min(parsed, maxPageSize)
+		}
+
+		results, err := notifications.ListByUser(requestCtx, userID, limit)
+		if err != nil {
+			log.Printf("Failed to list notifications for user %s: %v", userID, err)
+			http.Error(w, "Failed to fetch notifications", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: "Fetched notifications",
+			Data:    results,
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
+	"github.com/dcode-github/property_lisitng_system/backend/filter"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxSearchLength = 200
+
+// propertySearch is everything GetAllProperties reads from the query string.
+type propertySearch struct {
+	Page   pageRequest
+	Facets store.FacetRequest
+	Text   string
+	Geo    geoSearch
+	Filter bson.M
+}
+
+// parsePropertySearch reports every invalid parameter at once as a
+// *filter.Error.
+func parsePropertySearch(query url.Values) (propertySearch, error) {
+	var (
+		search  propertySearch
+		invalid []filter.InvalidParam
+		err     error
+	)
+	addError := func(param string, err error) {
+		invalid = append(invalid, filter.InvalidParam{Param: param, Reason: err.Error()})
+	}
+
+	if search.Page, err = parsePageRequest(query); err != nil {
+		addError("", err)
+	}
+	if search.Facets, err = parseFacetRequest(query); err != nil {
+		addError("facets", err)
+	}
+	if search.Text, err = parseTextSearch(query); err != nil {
+		addError("q", err)
+	}
+	if search.Geo, err = parseGeoSearch(query); err != nil {
+		addError("", err)
+	}
+	if search.Filter, err = buildPropertyFilter(query); err != nil {
+		if filterErr, ok := err.(*filter.Error); ok {
+			invalid = append(invalid, filterErr.Invalid...)
+		} else {
+			addError("", err)
+		}
+	}
+	if len(invalid) > 0 {
+		return search, &filter.Error{Invalid: invalid}
+	}
+
+	if search.Text != "" {
+		search.Filter["$text"] = bson.M{"$search": search.Text}
+		search.Page.TextScore = true
+	}
+	if conditions := search.Geo.conditions(); len(conditions) > 0 {
+		existing, _ := search.Filter["$and"].([]bson.M)
+		search.Filter["$and"] = append(existing, conditions...)
+	}
+	return search, nil
+}
+
+// parseListRequest reads paging and filters for the favorites and
+// recommendations lists, which support no other search parameters.
+func parseListRequest(query url.Values) (pageRequest, bson.M, error) {
+	var invalid []filter.InvalidParam
+
+	page, err := parsePageRequest(query)
+	if err != nil {
+		invalid = append(invalid, filter.InvalidParam{Reason: err.Error()})
+	}
+	listFilter, err := filter.Build(query, listFilterIgnoredParams, filter.DefaultLimits)
+	if filterErr, ok := err.(*filter.Error); ok {
+		invalid = append(invalid, filterErr.Invalid...)
+	} else if err != nil {
+		invalid = append(invalid, filter.InvalidParam{Reason: err.Error()})
+	}
+
+	if len(invalid) > 0 {
+		return page, nil, &filter.Error{Invalid: invalid}
+	}
+	return page, listFilter, nil
+}
+
+var listFilterIgnoredParams = mergeParams(paginationParams, map[string]bool{"userID": true})
+
+func mergeParams(sets ...map[string]bool) map[string]bool {
+	merged := make(map[string]bool)
+	for _, set := range sets {
+		for key := range set {
+			merged[key] = true
+		}
+	}
+	return merged
+}
+
+// findOptions adds the location to sparse fieldsets when distances have to
+// be computed from it.
+func (s propertySearch) findOptions() store.FindOptions {
+	opts := s.Page.findOptions()
+	if s.Geo.Near != nil && len(opts.Fields) > 0 {
+		opts.Fields = append(opts.Fields, "location")
+	}
+	return opts
+}
+
+const maxSearchBodyBytes = 64 << 10
+
+// SearchProperties runs GetAllProperties with the JSON filter from the
+// request body, which can express OR groups. Paging, sorting and the other
+// search options are read from the query string as for GET.
+func SearchProperties(properties store.PropertyStore, favorites store.FavoriteStore, appCache cache.Cache) http.HandlerFunc {
+	list := GetAllProperties(properties, favorites, appCache)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Filter json.RawMessage `json:"filter"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSearchBodyBytes)).Decode(&body); err != nil {
+			log.Printf("Invalid request body for SearchProperties: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		query := r.URL.Query()
+		if query.Has(filter.GroupParam) {
+			writeQueryError(w, "SearchProperties", fmt.Errorf("filter must be given in the body only"))
+			return
+		}
+		if len(body.Filter) > 0 && string(body.Filter) != "null" {
+			// Re-encoding sorts object keys, so equivalent bodies share a
+			// cache entry.
+			var node interface{}
+			decoder := json.NewDecoder(bytes.NewReader(body.Filter))
+			decoder.UseNumber()
+			if err := decoder.Decode(&node); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+			canonical, err := json.Marshal(node)
+			if err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+			query.Set(filter.GroupParam, string(canonical))
+		}
+
+		listRequest := r.Clone(r.Context())
+		listRequest.URL.RawQuery = query.Encode()
+		list(w, listRequest)
+	}
+}
+
+// parseTextSearch turns the q parameter into a $text search string. Only
+// letters and digits are kept, so user input cannot form phrases or negations.
+func parseTextSearch(query url.Values) (string, error) {
+	raw := query.Get("q")
+	if len(raw) > maxSearchLength {
+		return "", fmt.Errorf("q must be at most %d characters", maxSearchLength)
+	}
+
+	terms := strings.FieldsFunc(raw, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(terms, " "), nil
+}
+
+// writeQueryError responds 400 with the invalid parameters listed in data.
+func writeQueryError(w http.ResponseWriter, handlerName string, err error) {
+	log.Printf("Invalid query parameters for %s: %v", handlerName, err)
+
+	var invalid []filter.InvalidParam
+	if filterErr, ok := err.(*filter.Error); ok {
+		invalid = filterErr.Invalid
+	} else {
+		invalid = []filter.InvalidParam{{Reason: err.Error()}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(models.APIResponse{
+		Success: false,
+		Message: "Invalid query parameters",
+		Data:    invalid,
+	})
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/dcode-github/property_lisitng_system/backend/validation"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxVerificationReasonLength = 500
+
+// verificationKeyFields describe what a moderator checked. Editing any of
+// them sends a listing back to pending.
+var verificationKeyFields = []string{
+	"title", "type", "price", "state", "city", "areaSqFt",
+	"bedrooms", "bathrooms", "listingType", "location",
+}
+
+type verificationStatus struct {
+	Status  string                     `json:"status"`
+	History []models.VerificationEvent `json:"history"`
+}
+
+// verificationReset returns the event that sends a listing back to pending
+// when an edit touches a key field, or nil. It is applied in the same write
+// as the edit so a listing can never keep its approval with changed details.
+func verificationReset(userID string, fields []string) *models.VerificationEvent {
+	var changed []string
+	for _, field := range fields {
+		if slices.Contains(verificationKeyFields, field) {
+			changed = append(changed, field)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return &models.VerificationEvent{
+		Status: models.VerificationPending,
+		Reason: "Edited " + strings.Join(changed, ", "),
+		By:     userID,
+		At:     time.Now(),
+	}
+}
+
+// ReviewProperty records a moderator's decision on a listing. Rejections
+// need a reason; it is optional for approvals.
+func ReviewProperty(properties store.PropertyStore, appCache cache.Cache, status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for ReviewProperty")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		propertyID := mux.Vars(r)["id"]
+		objID, err := primitive.ObjectIDFromHex(propertyID)
+		if err != nil {
+			log.Printf("Invalid property ID '%s' for ReviewProperty: %v", propertyID, err)
+			http.Error(w, "Invalid property ID", http.StatusBadRequest)
+			return
+		}
+
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				log.Printf("Invalid request body for ReviewProperty: %v", err)
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+		body.Reason = strings.TrimSpace(body.Reason)
+
+		var errs validation.Errors
+		if status == models.VerificationRejected && body.Reason == "" {
+			errs.Add("reason", "is required")
+		}
+		if utf8.RuneCountInString(body.Reason) > maxVerificationReasonLength {
+			errs.Add("reason", fmt.Sprintf("must have at most %d characters", maxVerificationReasonLength))
+		}
+		if len(errs) > 0 {
+			writeValidationError(w, "ReviewProperty", errs)
+			return
+		}
+
+		found, err := properties.SetVerification(requestCtx, objID, models.VerificationEvent{
+			Status: status,
+			Reason: body.Reason,
+			By:     userID,
+			At:     time.Now(),
+		}, "")
+		if err != nil {
+			log.Printf("Failed to set verification of property %s to %s: %v", propertyID, status, err)
+			http.Error(w, "Failed to update verification", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "Property not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Property %s %s by %s", propertyID, status, userID)
+
+		go func() {
+			invalidatePropertyCache(appCache)
+			invalidatePropertyDetailCache(context.Background(), appCache, objID)
+		}()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"message": "Property " + status})
+	}
+}
+
+// GetPropertyVerification shows the verification status and history of a
+// listing to its owner and to moderators.
+func GetPropertyVerification(properties store.PropertyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestCtx := r.Context()
+
+		userID, ok := requestCtx.Value(UserIDKey).(string)
+		if !ok {
+			log.Println("User ID missing in context for GetPropertyVerification")
+			http.Error(w, "User ID missing in context", http.StatusUnauthorized)
+			return
+		}
+
+		propertyID := mux.Vars(r)["id"]
+		objID, err := primitive.ObjectIDFromHex(propertyID)
+		if err != nil {
+			log.Printf("Invalid property ID '%s' for GetPropertyVerification: %v", propertyID, err)
+			http.Error(w, "Invalid property ID", http.StatusBadRequest)
+			return
+		}
+
+		results, err := properties.Find(requestCtx, bson.M{"_id": objID}, store.FindOptions{Limit: 1})
+		if err != nil {
+			log.Printf("Error fetching property %s for GetPropertyVerification: %v", propertyID, err)
+			http.Error(w, "Error fetching property", http.StatusInternalServerError)
+			return
+		}
+		if len(results) == 0 || results[0].CreatedBy != userID && !models.CanModerate(callerRole(requestCtx)) {
+			http.Error(w, "Property not found", http.StatusNotFound)
+			return
+		}
+
+		history := results[0].VerificationHistory
+		if history == nil {
+			history = []models.VerificationEvent{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: "Fetched verification status",
+			Data:    verificationStatus{Status: results[0].VerificationStatus, History: history},
+		})
+	}
+}
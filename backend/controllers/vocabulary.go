@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+)
+
+var termVocabularies = map[string][]string{
+	"amenities": models.AmenityVocabulary,
+	"tags":      models.TagVocabulary,
+}
+
+// unknownTerms describes the terms of field missing from its vocabulary, or
+// returns "" when all are known.
+func unknownTerms(field string, terms models.TermList) string {
+	if unknown := terms.Unknown(termVocabularies[field]); len(unknown) > 0 {
+		return "unknown terms: " + strings.Join(unknown, ", ")
+	}
+	return ""
+}
+
+func GetVocabulary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: "Fetched vocabulary",
+			Data:    termVocabularies,
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Op string
+
+const (
+	OpEq       Op = "eq"
+	OpNe       Op = "ne"
+	OpGt       Op = "gt"
+	OpGte      Op = "gte"
+	OpLt       Op = "lt"
+	OpLte      Op = "lte"
+	OpIn       Op = "in"
+	OpNin      Op = "nin"
+	OpAll      Op = "all"
+	OpContains Op = "contains"
+	OpBetween  Op = "between"
+)
+
+// fieldOperators are the operators valid for each field type. The first one
+// is used when a parameter names no operator.
+var fieldOperators = map[FieldType][]Op{
+	Number:   {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin, OpBetween},
+	Date:     {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpBetween},
+	Bool:     {OpEq, OpNe},
+	String:   {OpIn, OpEq, OpNe, OpContains},
+	TermList: {OpIn, OpAll, OpNin},
+}
+
+// Expr is a node of a parsed filter: a Condition, or an And or Or of
+// expressions.
+type Expr interface {
+	compile() bson.M
+}
+
+// Condition compares a field against typed values: float64 for numbers,
+// time.Time for dates, bool, or string. between takes an inclusive lower and
+// upper bound.
+type Condition struct {
+	Field  string
+	Type   FieldType
+	Op     Op
+	Values []interface{}
+}
+
+type (
+	And []Expr
+	Or  []Expr
+)
+
+// Compile turns a parsed filter into a MongoDB query.
+func Compile(expr Expr) bson.M {
+	if expr == nil {
+		return bson.M{}
+	}
+	return expr.compile()
+}
+
+func (a And) compile() bson.M {
+	if len(a) == 0 {
+		return bson.M{}
+	}
+	clauses := make([]bson.M, 0, len(a))
+	for _, e := range a {
+		clauses = append(clauses, e.compile())
+	}
+	return bson.M{"$and": clauses}
+}
+
+func (o Or) compile() bson.M {
+	clauses := make([]bson.M, 0, len(o))
+	for _, e := range o {
+		clauses = append(clauses, e.compile())
+	}
+	return bson.M{"$or": clauses}
+}
+
+func (c Condition) compile() bson.M {
+	switch c.Op {
+	case OpIn, OpNin, OpAll:
+		return bson.M{c.Field: bson.M{"$" + string(c.Op): c.Values}}
+	case OpEq, OpNe:
+		// Strings accept several values for eq and ne.
+		if len(c.Values) > 1 {
+			setOp := map[Op]string{OpEq: "$in", OpNe: "$nin"}[c.Op]
+			return bson.M{c.Field: bson.M{setOp: c.Values}}
+		}
+	case OpBetween:
+		return bson.M{c.Field: bson.M{"$gte": c.Values[0], "$lte": c.Values[1]}}
+	case OpContains:
+		// Values are matched literally, never as patterns.
+		var anyOf bson.A
+		for _, v := range c.Values {
+			anyOf = append(anyOf, bson.M{c.Field: primitive.Regex{Pattern: regexp.QuoteMeta(fmt.Sprint(v)), Options: "i"}})
+		}
+		return bson.M{"$or": anyOf}
+	}
+	return bson.M{c.Field: bson.M{"$" + string(c.Op): c.Values[0]}}
+}
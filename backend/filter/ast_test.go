@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCompile(t *testing.T) {
+	number := func(op Op, values ...interface{}) Condition {
+		return Condition{Field: "price", Type: Number, Op: op, Values: values}
+	}
+	city := func(op Op, values ...interface{}) Condition {
+		return Condition{Field: "city", Type: String, Op: op, Values: values}
+	}
+	amenities := func(op Op, values ...interface{}) Condition {
+		return Condition{Field: "amenities", Type: TermList, Op: op, Values: values}
+	}
+
+	tests := []struct {
+		name string
+		expr Expr
+		want bson.M
+	}{
+		{name: "nil", expr: nil, want: bson.M{}},
+		{name: "empty and", expr: And{}, want: bson.M{}},
+		{name: "eq", expr: number(OpEq, 1.0), want: bson.M{"price": bson.M{"$eq": 1.0}}},
+		{name: "ne", expr: number(OpNe, 1.0), want: bson.M{"price": bson.M{"$ne": 1.0}}},
+		{name: "gt", expr: number(OpGt, 1.0), want: bson.M{"price": bson.M{"$gt": 1.0}}},
+		{name: "gte", expr: number(OpGte, 1.0), want: bson.M{"price": bson.M{"$gte": 1.0}}},
+		{name: "lt", expr: number(OpLt, 1.0), want: bson.M{"price": bson.M{"$lt": 1.0}}},
+		{name: "lte", expr: number(OpLte, 1.0), want: bson.M{"price": bson.M{"$lte": 1.0}}},
+		{name: "in", expr: number(OpIn, 1.0, 2.0),
+			want: bson.M{"price": bson.M{"$in": []interface{}{1.0, 2.0}}}},
+		{name: "nin", expr: number(OpNin, 1.0, 2.0),
+			want: bson.M{"price": bson.M{"$nin": []interface{}{1.0, 2.0}}}},
+		{name: "between", expr: number(OpBetween, 1.0, 5.0),
+			want: bson.M{"price": bson.M{"$gte": 1.0, "$lte": 5.0}}},
+		{name: "all", expr: amenities(OpAll, "gym", "lift"),
+			want: bson.M{"amenities": bson.M{"$all": []interface{}{"gym", "lift"}}}},
+		{name: "term in", expr: amenities(OpIn, "gym"),
+			want: bson.M{"amenities": bson.M{"$in": []interface{}{"gym"}}}},
+		{name: "string eq with several values", expr: city(OpEq, "a", "b"),
+			want: bson.M{"city": bson.M{"$in": []interface{}{"a", "b"}}}},
+		{name: "string ne with several values", expr: city(OpNe, "a", "b"),
+			want: bson.M{"city": bson.M{"$nin": []interface{}{"a", "b"}}}},
+		{name: "contains", expr: city(OpContains, "pun"),
+			want: bson.M{"$or": bson.A{bson.M{"city": primitive.Regex{Pattern: "pun", Options: "i"}}}}},
+		{name: "contains escapes regex syntax", expr: city(OpContains, `a.b*(c)[d]^$|\`),
+			want: bson.M{"$or": bson.A{bson.M{"city": primitive.Regex{Pattern: `a\.b\*\(c\)\[d\]\^\$\|\\`, Options: "i"}}}}},
+		{name: "contains with several values", expr: city(OpContains, "a+", "b?"),
+			want: bson.M{"$or": bson.A{
+				bson.M{"city": primitive.Regex{Pattern: `a\+`, Options: "i"}},
+				bson.M{"city": primitive.Regex{Pattern: `b\?`, Options: "i"}},
+			}}},
+		{name: "and", expr: And{number(OpGt, 1.0), city(OpEq, "a")},
+			want: bson.M{"$and": []bson.M{
+				{"price": bson.M{"$gt": 1.0}},
+				{"city": bson.M{"$eq": "a"}},
+			}}},
+		{name: "or inside and", expr: And{Or{number(OpLt, 1.0), number(OpGt, 9.0)}},
+			want: bson.M{"$and": []bson.M{
+				{"$or": []bson.M{
+					{"price": bson.M{"$lt": 1.0}},
+					{"price": bson.M{"$gt": 9.0}},
+				}},
+			}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compile(tt.expr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileContainsMatchesLiterally(t *testing.T) {
+	tests := []struct {
+		value string
+		input string
+		want  bool
+	}{
+		{value: "a.b", input: "xa.by", want: true},
+		{value: "a.b", input: "axb", want: false},
+		{value: ".*", input: "anything", want: false},
+		{value: ".*", input: "has .* inside", want: true},
+		{value: "(", input: "open ( paren", want: true},
+		{value: "PUNE", input: "pune", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value+" in "+tt.input, func(t *testing.T) {
+			filter := Compile(Condition{Field: "city", Type: String, Op: OpContains, Values: []interface{}{tt.value}})
+			re := filter["$or"].(bson.A)[0].(bson.M)["city"].(primitive.Regex)
+			pattern := re.Pattern
+			if re.Options == "i" {
+				pattern = "(?i)" + pattern
+			}
+			if got := regexp.MustCompile(pattern).MatchString(tt.input); got != tt.want {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupParam carries a JSON filter that can express OR groups, e.g.
+//
+//	{"or": [{"city": "Pune"}, {"bedrooms[gte]": 3}]}
+//
+// A node is an object whose entries are ANDed: field[op] keys with a value,
+// and "and" or "or" keys holding an array of nodes. Values may be strings,
+// numbers, booleans or arrays of those.
+const GroupParam = "filter"
+
+const maxGroupDepth = 4
+
+// ParseGroup parses a JSON filter. Every condition counts towards
+// limits.MaxClauses, so callers combining it with other filters pass what is
+// left of their budget.
+func ParseGroup(data []byte, schema Schema, limits Limits) (Expr, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return nil, &Error{Invalid: []InvalidParam{{Param: GroupParam, Reason: "invalid JSON: " + err.Error()}}}
+	}
+
+	p := groupParser{schema: schema, limits: limits}
+	expr := p.parseNode(node, GroupParam, 1)
+	if p.clauses > limits.MaxClauses {
+		p.invalid = append(p.invalid, InvalidParam{
+			Param:  GroupParam,
+			Reason: fmt.Sprintf("%d conditions given, the maximum is %d", p.clauses, limits.MaxClauses),
+		})
+	}
+	if len(p.invalid) > 0 {
+		return nil, &Error{Invalid: p.invalid}
+	}
+	return expr, nil
+}
+
+type groupParser struct {
+	schema  Schema
+	limits  Limits
+	clauses int
+	invalid []InvalidParam
+}
+
+func (p *groupParser) fail(path string, format string, args ...interface{}) {
+	p.invalid = append(p.invalid, InvalidParam{Param: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (p *groupParser) parseNode(node interface{}, path string, depth int) Expr {
+	if depth > maxGroupDepth {
+		p.fail(path, "groups can be nested at most %d deep", maxGroupDepth)
+		return nil
+	}
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		p.fail(path, "expected an object")
+		return nil
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var and And
+	for _, key := range keys {
+		if key == "and" || key == "or" {
+			if group := p.parseGroup(key, object[key], path+"."+key, depth); group != nil {
+				and = append(and, group)
+			}
+			continue
+		}
+
+		p.clauses++
+		raw, err := rawValue(object[key])
+		if err != nil {
+			p.fail(path+"."+key, "%v", err)
+			continue
+		}
+		cond, err := parseCondition(key, raw, p.schema, p.limits)
+		if err != nil {
+			p.fail(path+"."+key, "%v", err)
+			continue
+		}
+		if len(cond.Values) > 0 {
+			and = append(and, cond)
+		}
+	}
+	if len(and) == 1 {
+		return and[0]
+	}
+	return and
+}
+
+func (p *groupParser) parseGroup(key string, children interface{}, path string, depth int) Expr {
+	list, ok := children.([]interface{})
+	if !ok || len(list) == 0 {
+		p.fail(path, "expected a non-empty array")
+		return nil
+	}
+
+	exprs := make([]Expr, 0, len(list))
+	for i, child := range list {
+		if expr := p.parseNode(child, fmt.Sprintf("%s[%d]", path, i), depth+1); expr != nil {
+			exprs = append(exprs, expr)
+		}
+	}
+	if key == "or" {
+		return Or(exprs)
+	}
+	return And(exprs)
+}
+
+// rawValue renders a JSON value the way it would appear in a query string.
+func rawValue(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return fmt.Sprint(value), nil
+	case []interface{}:
+		parts := make([]string, 0, len(value))
+		for _, item := range value {
+			if _, nested := item.([]interface{}); nested {
+				return "", fmt.Errorf("arrays cannot be nested")
+			}
+			part, err := rawValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ","), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", v)
+}
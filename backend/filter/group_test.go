@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseGroup(t *testing.T) {
+	pune := Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"Pune"}}
+	priceFrom3 := Condition{Field: "price", Type: Number, Op: OpGte, Values: []interface{}{3.0}}
+
+	tests := []struct {
+		name        string
+		json        string
+		limits      Limits
+		want        Expr
+		wantInvalid []InvalidParam
+	}{
+		{name: "single condition", json: `{"city":"Pune"}`, want: pune},
+		{name: "or group", json: `{"or":[{"city":"Pune"},{"price[gte]":3}]}`,
+			want: Or{pune, priceFrom3}},
+		{name: "conditions and groups are ANDed in key order",
+			json: `{"price[gte]":3,"and":[{"city":"Pune"},{"isVerified":false}]}`,
+			want: And{
+				And{pune, Condition{Field: "isVerified", Type: Bool, Op: OpEq, Values: []interface{}{false}}},
+				priceFrom3,
+			}},
+		{name: "array values", json: `{"price[in]":[1,2.5],"city[ne]":["a","b"]}`,
+			want: And{
+				Condition{Field: "city", Type: String, Op: OpNe, Values: []interface{}{"a", "b"}},
+				Condition{Field: "price", Type: Number, Op: OpIn, Values: []interface{}{1.0, 2.5}},
+			}},
+		{name: "bool given as string", json: `{"isVerified":"true"}`,
+			want: Condition{Field: "isVerified", Type: Bool, Op: OpEq, Values: []interface{}{true}}},
+		{name: "empty object", json: `{}`, want: And(nil)},
+
+		{name: "invalid JSON", json: `{"city":`,
+			wantInvalid: []InvalidParam{{Param: GroupParam, Reason: "invalid JSON: unexpected EOF"}}},
+		{name: "not an object", json: `[1]`,
+			wantInvalid: []InvalidParam{{Param: "filter", Reason: "expected an object"}}},
+		{name: "group is not an array", json: `{"or":{"city":"Pune"}}`,
+			wantInvalid: []InvalidParam{{Param: "filter.or", Reason: "expected a non-empty array"}}},
+		{name: "empty group", json: `{"and":[]}`,
+			wantInvalid: []InvalidParam{{Param: "filter.and", Reason: "expected a non-empty array"}}},
+		{name: "group member is not an object", json: `{"or":["Pune"]}`,
+			wantInvalid: []InvalidParam{{Param: "filter.or[0]", Reason: "expected an object"}}},
+		{name: "unknown field in group", json: `{"or":[{"city":"Pune"},{"colour":"red"}]}`,
+			wantInvalid: []InvalidParam{{Param: "filter.or[1].colour", Reason: `unknown filter field "colour"`}}},
+		{name: "type mismatch in group", json: `{"price[gt]":"cheap"}`,
+			wantInvalid: []InvalidParam{{Param: "filter.price[gt]", Reason: `"cheap" is not a number`}}},
+		{name: "unsupported operator in group", json: `{"isVerified[lt]":true}`,
+			wantInvalid: []InvalidParam{{Param: "filter.isVerified[lt]", Reason: `unsupported operator "lt" for boolean field isVerified`}}},
+		{name: "nested arrays", json: `{"price[in]":[[1]]}`,
+			wantInvalid: []InvalidParam{{Param: "filter.price[in]", Reason: "arrays cannot be nested"}}},
+		{name: "null value", json: `{"city":null}`,
+			wantInvalid: []InvalidParam{{Param: "filter.city", Reason: "unsupported value <nil>"}}},
+		{name: "object value", json: `{"city":{"eq":"Pune"}}`,
+			wantInvalid: []InvalidParam{{Param: "filter.city", Reason: "unsupported value map[eq:Pune]"}}},
+		{name: "nested too deep", json: `{"or":[{"or":[{"or":[{"or":[{"city":"Pune"}]}]}]}]}`,
+			wantInvalid: []InvalidParam{{Param: "filter.or[0].or[0].or[0].or[0]", Reason: "groups can be nested at most 4 deep"}}},
+		{name: "nested to the limit", json: `{"or":[{"or":[{"or":[{"city":"Pune"}]}]}]}`,
+			want: Or{Or{Or{pune}}}},
+		{name: "too many conditions", json: `{"or":[{"city":"a"},{"city":"b"},{"city":"c"}]}`,
+			limits:      Limits{MaxQueryLength: 2048, MaxClauses: 2, MaxValues: 50},
+			wantInvalid: []InvalidParam{{Param: GroupParam, Reason: "3 conditions given, the maximum is 2"}}},
+		{name: "too many values", json: `{"price[in]":[1,2,3]}`,
+			limits:      Limits{MaxQueryLength: 2048, MaxClauses: 20, MaxValues: 2},
+			wantInvalid: []InvalidParam{{Param: "filter.price[in]", Reason: "at most 2 values are allowed"}}},
+		{name: "every error is reported", json: `{"colour":"red","price":"x"}`,
+			wantInvalid: []InvalidParam{
+				{Param: "filter.colour", Reason: `unknown filter field "colour"`},
+				{Param: "filter.price", Reason: `"x" is not a number`},
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := tt.limits
+			if limits == (Limits{}) {
+				limits = DefaultLimits
+			}
+
+			got, err := ParseGroup([]byte(tt.json), testSchema, limits)
+			if tt.wantInvalid != nil {
+				var filterErr *Error
+				if !errors.As(err, &filterErr) {
+					t.Fatalf("error = %v, want *Error", err)
+				}
+				if !reflect.DeepEqual(filterErr.Invalid, tt.wantInvalid) {
+					t.Errorf("invalid = %+v, want %+v", filterErr.Invalid, tt.wantInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := &Error{Invalid: []InvalidParam{
+		{Reason: "3 filters given, the maximum is 2"},
+		{Param: "price", Reason: `"x" is not a number`},
+	}}
+	want := `3 filters given, the maximum is 2; price: "x" is not a number`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
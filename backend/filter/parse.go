@@ -0,0 +1,229 @@
+// Package filter parses property search query parameters of the form
+// field=value or field[op]=value into a typed expression, validated against a
+// field schema, and compiles it to a MongoDB query.
+package filter
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type Limits struct {
+	// MaxQueryLength bounds the encoded query string.
+	MaxQueryLength int
+	// MaxClauses bounds the number of filter parameters.
+	MaxClauses int
+	// MaxValues bounds the comma separated values of a single parameter.
+	MaxValues int
+}
+
+var DefaultLimits = Limits{MaxQueryLength: 2048, MaxClauses: 20, MaxValues: 50}
+
+type InvalidParam struct {
+	Param  string `json:"param,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Error lists every parameter that could not be turned into a condition.
+type Error struct {
+	Invalid []InvalidParam
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Invalid))
+	for _, p := range e.Invalid {
+		if p.Param == "" {
+			parts = append(parts, p.Reason)
+			continue
+		}
+		parts = append(parts, p.Param+": "+p.Reason)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Build parses query against PropertySchema and compiles the result.
+func Build(query url.Values, ignore map[string]bool, limits Limits) (bson.M, error) {
+	expr, err := Parse(query, PropertySchema, ignore, limits)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(expr), nil
+}
+
+// Parse reads every parameter not in ignore as a condition on a schema field
+// and ANDs them together. A parameter with an empty value is skipped, one
+// given more than once is rejected, and GroupParam holds a JSON filter that
+// is ANDed with the rest.
+func Parse(query url.Values, schema Schema, ignore map[string]bool, limits Limits) (Expr, error) {
+	if n := len(query.Encode()); n > limits.MaxQueryLength {
+		return nil, &Error{Invalid: []InvalidParam{{
+			Reason: fmt.Sprintf("query is %d characters, the maximum is %d", n, limits.MaxQueryLength),
+		}}}
+	}
+
+	var params []string
+	for rawKey, values := range query {
+		if ignore[rawKey] || len(values) == 0 || len(values) == 1 && values[0] == "" {
+			continue
+		}
+		params = append(params, rawKey)
+	}
+	sort.Strings(params)
+	if len(params) > limits.MaxClauses {
+		return nil, &Error{Invalid: []InvalidParam{{
+			Reason: fmt.Sprintf("%d filters given, the maximum is %d", len(params), limits.MaxClauses),
+		}}}
+	}
+
+	var (
+		and     And
+		invalid []InvalidParam
+	)
+	for _, rawKey := range params {
+		if len(query[rawKey]) > 1 {
+			invalid = append(invalid, InvalidParam{Param: rawKey, Reason: "given more than once"})
+			continue
+		}
+		if rawKey == GroupParam {
+			// The group's conditions share the budget with the other filters.
+			groupLimits := limits
+			groupLimits.MaxClauses -= len(params) - 1
+			group, err := ParseGroup([]byte(query.Get(rawKey)), schema, groupLimits)
+			if filterErr, ok := err.(*Error); ok {
+				invalid = append(invalid, filterErr.Invalid...)
+			} else if group != nil {
+				and = append(and, group)
+			}
+			continue
+		}
+
+		cond, err := parseCondition(rawKey, query.Get(rawKey), schema, limits)
+		if err != nil {
+			invalid = append(invalid, InvalidParam{Param: rawKey, Reason: err.Error()})
+			continue
+		}
+		if len(cond.Values) > 0 {
+			and = append(and, cond)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, &Error{Invalid: invalid}
+	}
+	return and, nil
+}
+
+func parseCondition(rawKey, raw string, schema Schema, limits Limits) (Condition, error) {
+	field, opKey := rawKey, ""
+	if strings.Contains(rawKey, "[") && strings.HasSuffix(rawKey, "]") {
+		parts := strings.SplitN(strings.TrimSuffix(rawKey, "]"), "[", 2)
+		field, opKey = parts[0], parts[1]
+	}
+
+	fieldType, ok := schema[field]
+	if !ok {
+		return Condition{}, fmt.Errorf("unknown filter field %q", field)
+	}
+	op := fieldOperators[fieldType][0]
+	if opKey != "" {
+		op = Op(opKey)
+		if !slices.Contains(fieldOperators[fieldType], op) {
+			return Condition{}, fmt.Errorf("unsupported operator %q for %s field %s", opKey, fieldType, field)
+		}
+	}
+
+	cond := Condition{Field: field, Type: fieldType, Op: op}
+	switch fieldType {
+	case String, TermList:
+		var values []string
+		if fieldType == TermList {
+			values = models.ParseTermList(raw)
+		} else {
+			values = splitValues(raw)
+		}
+		if len(values) > limits.MaxValues {
+			return cond, fmt.Errorf("at most %d values are allowed", limits.MaxValues)
+		}
+		for _, v := range values {
+			cond.Values = append(cond.Values, v)
+		}
+	default:
+		rawValues := []string{raw}
+		if op == OpIn || op == OpNin || op == OpBetween {
+			rawValues = splitValues(raw)
+		}
+		if len(rawValues) > limits.MaxValues {
+			return cond, fmt.Errorf("at most %d values are allowed", limits.MaxValues)
+		}
+		for _, rv := range rawValues {
+			value, err := parseValue(fieldType, rv)
+			if err != nil {
+				return cond, err
+			}
+			cond.Values = append(cond.Values, value)
+		}
+		if op == OpBetween {
+			if len(cond.Values) != 2 {
+				return cond, fmt.Errorf("between takes a lower and an upper bound")
+			}
+			if compareBounds(cond.Values[0], cond.Values[1]) > 0 {
+				return cond, fmt.Errorf("between bounds are in the wrong order")
+			}
+		}
+	}
+	return cond, nil
+}
+
+// parseValue converts a single raw value of a scalar field type.
+func parseValue(fieldType FieldType, raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	switch fieldType {
+	case Number:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return v, nil
+	case Date:
+		v, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a date in YYYY-MM-DD format", raw)
+		}
+		return v, nil
+	case Bool:
+		v, err := strconv.ParseBool(strings.ToLower(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return v, nil
+	}
+	return raw, nil
+}
+
+func compareBounds(lower, upper interface{}) int {
+	switch l := lower.(type) {
+	case float64:
+		return cmp.Compare(l, upper.(float64))
+	case time.Time:
+		return l.Compare(upper.(time.Time))
+	}
+	return 0
+}
+
+func splitValues(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,251 @@
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"price":         Number,
+	"availableFrom": Date,
+	"isVerified":    Bool,
+	"city":          String,
+	"amenities":     TermList,
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// values returns n comma separated numbers.
+func values(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = "1"
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		raw     string
+		want    Condition
+		wantErr string
+	}{
+		{name: "number default eq", key: "price", raw: "100",
+			want: Condition{Field: "price", Type: Number, Op: OpEq, Values: []interface{}{100.0}}},
+		{name: "number ne", key: "price[ne]", raw: "100",
+			want: Condition{Field: "price", Type: Number, Op: OpNe, Values: []interface{}{100.0}}},
+		{name: "number gt", key: "price[gt]", raw: "1.5",
+			want: Condition{Field: "price", Type: Number, Op: OpGt, Values: []interface{}{1.5}}},
+		{name: "number gte", key: "price[gte]", raw: "2",
+			want: Condition{Field: "price", Type: Number, Op: OpGte, Values: []interface{}{2.0}}},
+		{name: "number lt", key: "price[lt]", raw: "3",
+			want: Condition{Field: "price", Type: Number, Op: OpLt, Values: []interface{}{3.0}}},
+		{name: "number lte", key: "price[lte]", raw: " 4 ",
+			want: Condition{Field: "price", Type: Number, Op: OpLte, Values: []interface{}{4.0}}},
+		{name: "number in", key: "price[in]", raw: "1, 2,,3",
+			want: Condition{Field: "price", Type: Number, Op: OpIn, Values: []interface{}{1.0, 2.0, 3.0}}},
+		{name: "number nin", key: "price[nin]", raw: "1,2",
+			want: Condition{Field: "price", Type: Number, Op: OpNin, Values: []interface{}{1.0, 2.0}}},
+		{name: "number between", key: "price[between]", raw: "1,5",
+			want: Condition{Field: "price", Type: Number, Op: OpBetween, Values: []interface{}{1.0, 5.0}}},
+		{name: "number between equal bounds", key: "price[between]", raw: "5,5",
+			want: Condition{Field: "price", Type: Number, Op: OpBetween, Values: []interface{}{5.0, 5.0}}},
+		{name: "date eq", key: "availableFrom", raw: "2024-03-01",
+			want: Condition{Field: "availableFrom", Type: Date, Op: OpEq, Values: []interface{}{date("2024-03-01")}}},
+		{name: "date between", key: "availableFrom[between]", raw: "2024-01-01,2024-12-31",
+			want: Condition{Field: "availableFrom", Type: Date, Op: OpBetween, Values: []interface{}{date("2024-01-01"), date("2024-12-31")}}},
+		{name: "bool eq", key: "isVerified", raw: "true",
+			want: Condition{Field: "isVerified", Type: Bool, Op: OpEq, Values: []interface{}{true}}},
+		{name: "bool ne is case insensitive", key: "isVerified[ne]", raw: "FALSE",
+			want: Condition{Field: "isVerified", Type: Bool, Op: OpNe, Values: []interface{}{false}}},
+		{name: "string default in", key: "city", raw: "Pune, Mumbai",
+			want: Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"Pune", "Mumbai"}}},
+		{name: "string eq", key: "city[eq]", raw: "Pune",
+			want: Condition{Field: "city", Type: String, Op: OpEq, Values: []interface{}{"Pune"}}},
+		{name: "string ne", key: "city[ne]", raw: "Pune",
+			want: Condition{Field: "city", Type: String, Op: OpNe, Values: []interface{}{"Pune"}}},
+		{name: "string contains", key: "city[contains]", raw: "a.b*",
+			want: Condition{Field: "city", Type: String, Op: OpContains, Values: []interface{}{"a.b*"}}},
+		{name: "terms default in are normalized", key: "amenities", raw: "Swimming Pool|gym",
+			want: Condition{Field: "amenities", Type: TermList, Op: OpIn, Values: []interface{}{"gym", "swimming-pool"}}},
+		{name: "terms all", key: "amenities[all]", raw: "gym,lift",
+			want: Condition{Field: "amenities", Type: TermList, Op: OpAll, Values: []interface{}{"gym", "lift"}}},
+		{name: "terms nin", key: "amenities[nin]", raw: "gym",
+			want: Condition{Field: "amenities", Type: TermList, Op: OpNin, Values: []interface{}{"gym"}}},
+
+		{name: "unknown field", key: "colour", raw: "red",
+			wantErr: `unknown filter field "colour"`},
+		{name: "unknown field with operator", key: "colour[eq]", raw: "red",
+			wantErr: `unknown filter field "colour"`},
+		{name: "unknown operator", key: "price[foo]", raw: "1",
+			wantErr: `unsupported operator "foo" for number field price`},
+		{name: "contains on number", key: "price[contains]", raw: "1",
+			wantErr: `unsupported operator "contains" for number field price`},
+		{name: "range on bool", key: "isVerified[gt]", raw: "true",
+			wantErr: `unsupported operator "gt" for boolean field isVerified`},
+		{name: "between on string", key: "city[between]", raw: "a,b",
+			wantErr: `unsupported operator "between" for string field city`},
+		{name: "all on string", key: "city[all]", raw: "a",
+			wantErr: `unsupported operator "all" for string field city`},
+		{name: "contains on terms", key: "amenities[contains]", raw: "gym",
+			wantErr: `unsupported operator "contains" for term list field amenities`},
+		{name: "number mismatch", key: "price", raw: "abc",
+			wantErr: `"abc" is not a number`},
+		{name: "number mismatch in list", key: "price[in]", raw: "1,two",
+			wantErr: `"two" is not a number`},
+		{name: "date mismatch", key: "availableFrom[gte]", raw: "01/02/2024",
+			wantErr: `"01/02/2024" is not a date in YYYY-MM-DD format`},
+		{name: "bool mismatch", key: "isVerified", raw: "maybe",
+			wantErr: `"maybe" is not a boolean`},
+		{name: "between with one bound", key: "price[between]", raw: "1",
+			wantErr: "between takes a lower and an upper bound"},
+		{name: "between with three bounds", key: "price[between]", raw: "1,2,3",
+			wantErr: "between takes a lower and an upper bound"},
+		{name: "between reversed", key: "price[between]", raw: "5,1",
+			wantErr: "between bounds are in the wrong order"},
+		{name: "date between reversed", key: "availableFrom[between]", raw: "2024-12-31,2024-01-01",
+			wantErr: "between bounds are in the wrong order"},
+		{name: "too many number values", key: "price[in]", raw: values(DefaultLimits.MaxValues + 1),
+			wantErr: "at most 50 values are allowed"},
+		{name: "too many string values", key: "city", raw: values(DefaultLimits.MaxValues + 1),
+			wantErr: "at most 50 values are allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCondition(tt.key, tt.raw, testSchema, DefaultLimits)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		ignore      map[string]bool
+		limits      Limits
+		want        Expr
+		wantInvalid []InvalidParam
+	}{
+		{name: "no filters", query: "", want: And(nil)},
+		{name: "ignored and empty params are skipped", query: "limit=5&city=&sort=-price",
+			ignore: map[string]bool{"limit": true, "sort": true}, want: And(nil)},
+		{name: "conditions are ANDed in parameter order", query: "price[gte]=10&city=Pune",
+			want: And{
+				Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"Pune"}},
+				Condition{Field: "price", Type: Number, Op: OpGte, Values: []interface{}{10.0}},
+			}},
+		{name: "empty term list is dropped", query: "amenities=,|",
+			want: And(nil)},
+		{name: "group param is ANDed with the rest",
+			query: "city=Pune&" + url.Values{GroupParam: {`{"or":[{"price[lt]":5},{"isVerified":true}]}`}}.Encode(),
+			want: And{
+				Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"Pune"}},
+				Or{
+					Condition{Field: "price", Type: Number, Op: OpLt, Values: []interface{}{5.0}},
+					Condition{Field: "isVerified", Type: Bool, Op: OpEq, Values: []interface{}{true}},
+				},
+			}},
+		{name: "every invalid param is reported", query: "price=abc&colour=red&city=Pune",
+			wantInvalid: []InvalidParam{
+				{Param: "colour", Reason: `unknown filter field "colour"`},
+				{Param: "price", Reason: `"abc" is not a number`},
+			}},
+		{name: "group errors are reported with the rest", query: "price=abc&" + url.Values{GroupParam: {`{"colour":1}`}}.Encode(),
+			wantInvalid: []InvalidParam{
+				{Param: "filter.colour", Reason: `unknown filter field "colour"`},
+				{Param: "price", Reason: `"abc" is not a number`},
+			}},
+		{name: "repeated params are rejected", query: "city=Pune&city=Delhi&price[gte]=1&price[gte]=2&isVerified=true",
+			wantInvalid: []InvalidParam{
+				{Param: "city", Reason: "given more than once"},
+				{Param: "price[gte]", Reason: "given more than once"},
+			}},
+		{name: "repeated empty params are rejected", query: "city=&city=Pune",
+			wantInvalid: []InvalidParam{{Param: "city", Reason: "given more than once"}}},
+		{name: "repeated ignored params are skipped", query: "limit=1&limit=2",
+			ignore: map[string]bool{"limit": true}, want: And(nil)},
+		{name: "query too long", query: "city=" + strings.Repeat("a", 20),
+			limits:      Limits{MaxQueryLength: 20, MaxClauses: 20, MaxValues: 50},
+			wantInvalid: []InvalidParam{{Reason: "query is 25 characters, the maximum is 20"}}},
+		{name: "too many filters", query: "city=a&price=1&isVerified=true",
+			limits:      Limits{MaxQueryLength: 2048, MaxClauses: 2, MaxValues: 50},
+			wantInvalid: []InvalidParam{{Reason: "3 filters given, the maximum is 2"}}},
+		{name: "ignored params do not count as filters", query: "city=a&limit=1&sort=price",
+			ignore: map[string]bool{"limit": true, "sort": true},
+			limits: Limits{MaxQueryLength: 2048, MaxClauses: 1, MaxValues: 50},
+			want:   And{Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"a"}}}},
+		{name: "group conditions share the filter budget",
+			query:       "city=a&price=1&" + url.Values{GroupParam: {`{"or":[{"city":"b"},{"city":"c"}]}`}}.Encode(),
+			limits:      Limits{MaxQueryLength: 2048, MaxClauses: 3, MaxValues: 50},
+			wantInvalid: []InvalidParam{{Param: GroupParam, Reason: "2 conditions given, the maximum is 1"}}},
+		{name: "group conditions within the filter budget",
+			query:  "city=a&" + url.Values{GroupParam: {`{"or":[{"city":"b"},{"city":"c"}]}`}}.Encode(),
+			limits: Limits{MaxQueryLength: 2048, MaxClauses: 3, MaxValues: 50},
+			want: And{
+				Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"a"}},
+				Or{
+					Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"b"}},
+					Condition{Field: "city", Type: String, Op: OpIn, Values: []interface{}{"c"}},
+				},
+			}},
+		{name: "too many values", query: "price[in]=1,2,3",
+			limits:      Limits{MaxQueryLength: 2048, MaxClauses: 20, MaxValues: 2},
+			wantInvalid: []InvalidParam{{Param: "price[in]", Reason: "at most 2 values are allowed"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			limits := tt.limits
+			if limits == (Limits{}) {
+				limits = DefaultLimits
+			}
+
+			got, err := Parse(query, testSchema, tt.ignore, limits)
+			if tt.wantInvalid != nil {
+				var filterErr *Error
+				if !errors.As(err, &filterErr) {
+					t.Fatalf("error = %v, want *Error", err)
+				}
+				if !reflect.DeepEqual(filterErr.Invalid, tt.wantInvalid) {
+					t.Errorf("invalid = %+v, want %+v", filterErr.Invalid, tt.wantInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
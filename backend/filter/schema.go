@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+)
+
+type FieldType int
+
+const (
+	Number FieldType = iota + 1
+	Date
+	Bool
+	String
+	// TermList is an array of vocabulary terms.
+	TermList
+)
+
+func (t FieldType) String() string {
+	switch t {
+	case Number:
+		return "number"
+	case Date:
+		return "date"
+	case Bool:
+		return "boolean"
+	case String:
+		return "string"
+	case TermList:
+		return "term list"
+	}
+	return "unknown"
+}
+
+// Schema maps the stored name of each filterable field to its type.
+type Schema map[string]FieldType
+
+// PropertySchema lists the filterable fields of models.Property.
+var PropertySchema = SchemaFor(reflect.TypeOf(models.Property{}))
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	termListType = reflect.TypeOf(models.TermList{})
+)
+
+// SchemaFor derives a schema from the bson tags of a struct. Fields that are
+// not stored, have no filterable type, or are tagged filter:"-" are left out.
+func SchemaFor(t reflect.Type) Schema {
+	schema := Schema{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("bson"), ",")[0]
+		if name == "" || name == "-" || name == "_id" || field.Tag.Get("filter") == "-" {
+			continue
+		}
+
+		switch {
+		case field.Type == timeType:
+			schema[name] = Date
+		case field.Type == termListType:
+			schema[name] = TermList
+		default:
+			switch field.Type.Kind() {
+			case reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+				schema[name] = Number
+			case reflect.Bool:
+				schema[name] = Bool
+			case reflect.String:
+				schema[name] = String
+			}
+		}
+	}
+	return schema
+}
+
+// Sortable reports whether field has an ordering that makes sense to sort
+// by.
+func (s Schema) Sortable(field string) bool {
+	return s[field] == Number || s[field] == Date
+}
@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSchemaFor(t *testing.T) {
+	type document struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Count    int                `bson:"count"`
+		Ratio    float64            `bson:"ratio,omitempty"`
+		At       time.Time          `bson:"at"`
+		Active   bool               `bson:"active"`
+		Name     string             `bson:"name"`
+		Terms    models.TermList    `bson:"terms"`
+		Hidden   string             `bson:"hidden" filter:"-"`
+		Computed float64            `bson:"-"`
+		Location *models.GeoPoint   `bson:"location"`
+		Untagged string
+	}
+
+	want := Schema{
+		"count":  Number,
+		"ratio":  Number,
+		"at":     Date,
+		"active": Bool,
+		"name":   String,
+		"terms":  TermList,
+	}
+	if got := SchemaFor(reflect.TypeOf(document{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPropertySchema(t *testing.T) {
+	tests := []struct {
+		field    string
+		want     FieldType
+		sortable bool
+	}{
+		{field: "price", want: Number, sortable: true},
+		{field: "rating", want: Number, sortable: true},
+		{field: "availableFrom", want: Date, sortable: true},
+		{field: "isVerified", want: Bool},
+		{field: "city", want: String},
+		{field: "verificationStatus", want: String},
+		{field: "amenities", want: TermList},
+		{field: "tags", want: TermList},
+		{field: "_id"},
+		{field: "score"},
+		{field: "isFav"},
+		{field: "location"},
+		{field: "verificationHistory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := PropertySchema[tt.field]; got != tt.want {
+				t.Errorf("type = %v, want %v", got, tt.want)
+			}
+			if got := PropertySchema.Sortable(tt.field); got != tt.sortable {
+				t.Errorf("sortable = %v, want %v", got, tt.sortable)
+			}
+		})
+	}
+}
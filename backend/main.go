@@ -8,13 +8,13 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
 	"github.com/dcode-github/property_lisitng_system/backend/config"
 	"github.com/dcode-github/property_lisitng_system/backend/routes"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
-	"github.com/redis/go-redis/v9"
 	"github.com/rs/cors"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func loadEnv() {
@@ -23,32 +23,50 @@ func loadEnv() {
 	}
 }
 
-func setupRouter(client *mongo.Client, redisClient *redis.Client) *mux.Router {
+func setupRouter(stores *store.Store, appCache cache.Cache, cfg routes.Config) *mux.Router {
 	router := mux.NewRouter()
-	routes.Routes(router, client, redisClient)
+	routes.Routes(router, stores, appCache, cfg)
 	return router
 }
 
 func main() {
 	loadEnv()
 
-	client, err := config.ConnectDB()
-	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
-	}
-	defer func() {
-		if err := client.Disconnect(context.TODO()); err != nil {
-			log.Fatalf("Error closing MongoDB connection: %v", err)
+	var stores *store.Store
+	if config.StoreBackend() == config.StoreBackendMemory {
+		log.Println("Using in-memory store, data will not survive a restart")
+		stores = store.NewMemoryStore()
+	} else {
+		client, err := config.ConnectDB()
+		if err != nil {
+			log.Fatalf("Failed to connect to the database: %v", err)
 		}
-		log.Println("MongoDB connection closed")
-	}()
-
-	redisClient := config.InitRedis()
-	defer redisClient.Close()
+		defer func() {
+			if err := client.Disconnect(context.TODO()); err != nil {
+				log.Fatalf("Error closing MongoDB connection: %v", err)
+			}
+			log.Println("MongoDB connection closed")
+		}()
+
+		db := config.Database(client)
+		indexCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err = store.EnsureMongoIndexes(indexCtx, db)
+		cancel()
+		if err != nil {
+			log.Fatalf("Failed to create database indexes: %v", err)
+		}
+		stores = store.NewMongoStore(db)
+	}
 
-	config.InitCollections(client)
+	appCache, closeCache := config.InitCache()
+	defer closeCache()
 
-	router := setupRouter(client, redisClient)
+	router := setupRouter(stores, appCache, routes.Config{
+		Limiter:      config.InitRateLimiter(),
+		Lifetimes:    config.TokenLifetimes(),
+		AdminUserIDs: config.AdminUserIDs(),
+		Mailer:       config.InitMailer(),
+	})
 
 	corsOptions := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
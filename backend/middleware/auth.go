@@ -7,37 +7,99 @@ import (
 	"strings"
 
 	"github.com/dcode-github/property_lisitng_system/backend/controllers"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
 	"github.com/dcode-github/property_lisitng_system/backend/utils"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+const (
+	missingHeaderMessage = "Missing Authorization header"
+	headerFormatMessage  = "Invalid Authorization header format"
+	invalidTokenMessage  = "Invalid or expired token"
+)
+
+// bearerClaims validates the bearer token of r. On failure it returns the
+// message to send to the client instead.
+func bearerClaims(r *http.Request) (*utils.Claims, string) {
+	tokenHeader := r.Header.Get("Authorization")
+	if tokenHeader == "" {
+		return nil, missingHeaderMessage
+	}
+
+	tokenParts := strings.Split(tokenHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		log.Printf("Invalid Authorization header format from request %s %s", r.Method, r.URL)
+		return nil, headerFormatMessage
+	}
+
+	claims, err := utils.ValidateJWT(tokenParts[1])
+	if err != nil {
+		log.Printf("Invalid or expired token: %v", err)
+		return nil, invalidTokenMessage
+	}
+	return claims, ""
+}
 
-		tokenHeader := r.Header.Get("Authorization")
-		if tokenHeader == "" {
-			log.Printf("Missing Authorization header from request %s %s", r.Method, r.URL)
-			http.Error(w, "Missing Authorization header", http.StatusUnauthorized)
-			return
-		}
+// authenticate validates the bearer token of r and rejects revoked tokens.
+// On failure it returns the status and message to send to the client.
+func authenticate(r *http.Request, revocations store.RevocationStore) (*utils.Claims, int, string) {
+	claims, problem := bearerClaims(r)
+	if problem != "" {
+		return nil, http.StatusUnauthorized, problem
+	}
 
-		tokenParts := strings.Split(tokenHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			log.Printf("Invalid Authorization header format from request %s %s", r.Method, r.URL)
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
-			return
-		}
+	revoked, err := revocations.IsRevoked(r.Context(), claims.Id, claims.UserID, claims.TokenVersion)
+	if err != nil {
+		log.Printf("Failed to check revocation of token for user %s: %v", claims.UserID, err)
+		return nil, http.StatusInternalServerError, "Failed to verify token"
+	}
+	if revoked {
+		log.Printf("Revoked token presented for user %s", claims.UserID)
+		return nil, http.StatusUnauthorized, "Token has been revoked"
+	}
+	return claims, 0, ""
+}
+
+func withClaims(r *http.Request, claims *utils.Claims) *http.Request {
+	ctx := context.WithValue(r.Context(), controllers.UserIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, controllers.ClaimsKey, claims)
+	return r.WithContext(ctx)
+}
 
-		token := tokenParts[1]
+func AuthMiddleware(revocations store.RevocationStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, status, problem := authenticate(r, revocations)
+			if problem == missingHeaderMessage {
+				log.Printf("Missing Authorization header from request %s %s", r.Method, r.URL)
+			}
+			if problem != "" {
+				http.Error(w, problem, status)
+				return
+			}
+
+			next.ServeHTTP(w, withClaims(r, claims))
+		})
+	}
+}
 
-		claims, err := utils.ValidateJWT(token)
-		if err != nil {
-			log.Printf("Invalid or expired token: %v", err)
-			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-			return
-		}
+// OptionalAuthMiddleware lets requests without an Authorization header
+// through anonymously, leaving the user ID out of the context. A header that
+// is present must still carry a valid token.
+func OptionalAuthMiddleware(revocations store.RevocationStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		ctx := context.WithValue(r.Context(), controllers.UserIDKey, claims.UserID)
+			claims, status, problem := authenticate(r, revocations)
+			if problem != "" {
+				http.Error(w, problem, status)
+				return
+			}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			next.ServeHTTP(w, withClaims(r, claims))
+		})
+	}
 }
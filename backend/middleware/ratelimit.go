@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/controllers"
+)
+
+// RateLimiter allows each client a fixed number of requests per window.
+// Signed in users are counted by user ID and anonymous callers by IP address.
+// Counts are kept in process, so every server instance enforces its own
+// limit.
+type RateLimiter struct {
+	window        time.Duration
+	authenticated int
+	anonymous     int
+
+	mu        sync.Mutex
+	counters  map[string]*rateCounter
+	lastSweep time.Time
+}
+
+type rateCounter struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter limits clients to the given number of requests per window.
+// A limit of zero disables limiting for that kind of client.
+func NewRateLimiter(window time.Duration, authenticated, anonymous int) *RateLimiter {
+	return &RateLimiter{
+		window:        window,
+		authenticated: authenticated,
+		anonymous:     anonymous,
+		counters:      make(map[string]*rateCounter),
+		lastSweep:     time.Now(),
+	}
+}
+
+// Middleware must run after the auth middleware so the user ID is known.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, limit := "ip:"+clientIP(r), l.anonymous
+		if userID, ok := r.Context().Value(controllers.UserIDKey).(string); ok {
+			key, limit = "user:"+userID, l.authenticated
+		}
+
+		if allowed, retryAfter := l.allow(key, limit, time.Now()); !allowed {
+			log.Printf("Rate limit exceeded for %s on %s %s", key, r.Method, r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *RateLimiter) allow(key string, limit int, now time.Time) (bool, time.Duration) {
+	if limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.window {
+		for k, c := range l.counters {
+			if now.Sub(c.start) >= l.window {
+				delete(l.counters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.counters[key]
+	if !ok || now.Sub(c.start) >= l.window {
+		c = &rateCounter{start: now}
+		l.counters[key] = c
+	}
+	if c.count >= limit {
+		return false, c.start.Add(l.window).Sub(now)
+	}
+	c.count++
+	return true, 0
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/dcode-github/property_lisitng_system/backend/controllers"
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+)
+
+// RequireRole only lets callers whose access token carries one of roles
+// through. It must run after AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(controllers.ClaimsKey).(*utils.Claims)
+			if !ok || !slices.Contains(roles, claims.Role) {
+				log.Printf("Forbidden %s %s for caller without role %v", r.Method, r.URL.Path, roles)
+				http.Error(w, "Insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/dcode-github/property_lisitng_system/backend/utils"
+
+// GeoPoint is a GeoJSON point. Coordinates are [longitude, latitude].
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+func NewGeoPoint(lat, lng float64) *GeoPoint {
+	return &GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}
+}
+
+func (p *GeoPoint) Lat() float64 { return p.Coordinates[1] }
+func (p *GeoPoint) Lng() float64 { return p.Coordinates[0] }
+
+func (p *GeoPoint) Valid() bool {
+	return p.Type == "Point" && len(p.Coordinates) == 2 && utils.ValidCoordinates(p.Coordinates[1], p.Coordinates[0])
+}
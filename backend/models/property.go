@@ -6,27 +6,39 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Property fields carry the rules CreateProperty and UpdateProperty enforce,
+// see the validation package.
 type Property struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
 	PropId        string             `bson:"id" json:"id"`
-	Title         string             `bson:"title" json:"title"`
-	Type          string             `bson:"type" json:"type"`
-	Price         int                `bson:"price" json:"price"`
-	State         string             `bson:"state" json:"state"`
-	City          string             `bson:"city" json:"city"`
-	AreaSqFt      int                `bson:"areaSqFt" json:"areaSqFt"`
-	Bedrooms      int                `bson:"bedrooms" json:"bedrooms"`
-	Bathrooms     int                `bson:"bathrooms" json:"bathrooms"`
-	Amenities     string             `bson:"amenities" json:"amenities"`
-	Furnished     string             `bson:"furnished" json:"furnished"`
+	Title         string             `bson:"title" json:"title" validate:"required,max=200"`
+	Type          string             `bson:"type" json:"type" validate:"required,oneof=Apartment|Villa|Bungalow|Studio|Penthouse"`
+	Price         int                `bson:"price" json:"price" validate:"min=1"`
+	State         string             `bson:"state" json:"state" validate:"required,max=100"`
+	City          string             `bson:"city" json:"city" validate:"required,max=100"`
+	AreaSqFt      int                `bson:"areaSqFt" json:"areaSqFt" validate:"min=1"`
+	Bedrooms      int                `bson:"bedrooms" json:"bedrooms" validate:"min=0,max=50"`
+	Bathrooms     int                `bson:"bathrooms" json:"bathrooms" validate:"min=0,max=50"`
+	Amenities     TermList           `bson:"amenities" json:"amenities" validate:"max=30"`
+	Furnished     string             `bson:"furnished" json:"furnished" validate:"required,oneof=Furnished|Unfurnished|Semi"`
 	AvailableFrom time.Time          `bson:"availableFrom" json:"availableFrom"`
-	ListedBy      string             `bson:"listedBy" json:"listedBy"`
-	Tags          string             `bson:"tags" json:"tags"`
-	ColorTheme    string             `bson:"colorTheme" json:"colorTheme"`
-	Rating        float64            `bson:"rating" json:"rating"`
+	ListedBy      string             `bson:"listedBy" json:"listedBy" validate:"required,oneof=Builder|Owner|Agent"`
+	Tags          TermList           `bson:"tags" json:"tags" validate:"max=30"`
+	ColorTheme    string             `bson:"colorTheme" json:"colorTheme" validate:"max=20"`
+	Rating        float64            `bson:"rating" json:"rating" validate:"min=0,max=5"`
 	IsVerified    bool               `bson:"isVerified" json:"isVerified"`
-	ListingType   string             `bson:"listingType" json:"listingType"`
+	ListingType   string             `bson:"listingType" json:"listingType" validate:"required,oneof=rent|sale"`
 	CreatedBy     string             `bson:"createdBy" json:"createdBy"`
+	Location      *GeoPoint          `bson:"location,omitempty" json:"location,omitempty"`
 	IsFavorite    bool               `bson:"-" json:"isFav"`
 	RecommendedBy string             `bson:"-" json:"recommendedBy"`
+	Score         float64            `bson:"-" json:"score,omitempty"`
+	DistanceKm    *float64           `bson:"-" json:"distanceKm,omitempty"`
+
+	// VerificationStatus is one of the Verification* statuses, or empty for
+	// listings created before the verification workflow until
+	// cmd/migrate-verification has run. The history is
+	// only served to the owner and moderators.
+	VerificationStatus  string              `bson:"verificationStatus" json:"verificationStatus"`
+	VerificationHistory []VerificationEvent `bson:"verificationHistory,omitempty" json:"-"`
 }
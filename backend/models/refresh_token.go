@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is the server side record of a refresh token. Only a hash of
+// the token is stored. Every rotation issues a new token in the same family,
+// so presenting a token that was already used reveals a stolen copy.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"userID" json:"userID"`
+	FamilyID  string             `bson:"familyID" json:"familyID"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UsedAt    *time.Time         `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	Revoked   bool               `bson:"revoked" json:"revoked"`
+	// TokenVersion is the user's token version when the token was issued.
+	TokenVersion int `bson:"tokenVersion" json:"-"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SavedSearch stores the query string of a property search, e.g.
+// "city=Pune&price[lte]=5000000".
+type SavedSearch struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"userID" json:"userID"`
+	Name      string             `bson:"name" json:"name"`
+	Query     string             `bson:"query" json:"query"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Notification tells a user that a listing matches one of their saved
+// searches.
+type Notification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"userID" json:"userID"`
+	SavedSearchID primitive.ObjectID `bson:"savedSearchID" json:"savedSearchID"`
+	PropertyID    primitive.ObjectID `bson:"propertyID" json:"propertyID"`
+	Message       string             `bson:"message" json:"message"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
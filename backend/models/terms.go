@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// AmenityVocabulary and TagVocabulary are the normalized terms a listing may
+// use for Amenities and Tags.
+var (
+	AmenityVocabulary = []string{
+		"clubhouse", "garden", "gym", "intercom", "lift", "park", "parking", "playground",
+		"pool", "power-backup", "security", "wifi",
+	}
+	TagVocabulary = []string{
+		"affordable", "corner-plot", "family-friendly", "gated-community", "lake-view", "luxury",
+		"near-metro", "new-launch", "pet-friendly", "ready-to-move", "sea-view", "vastu-compliant",
+	}
+)
+
+// TermList is a normalized, de-duplicated list of vocabulary terms. It also
+// decodes the legacy comma or pipe separated string form, from JSON and from
+// documents that have not been migrated yet.
+type TermList []string
+
+// NormalizeTerm lower-cases a term and joins its words with '-', so
+// "Power Backup" and "power_backup" both become "power-backup".
+func NormalizeTerm(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '\t'
+	})
+	return strings.Join(words, "-")
+}
+
+func NewTermList(terms []string) TermList {
+	seen := make(map[string]bool, len(terms))
+	list := TermList{}
+	for _, term := range terms {
+		term = NormalizeTerm(term)
+		if term != "" && !seen[term] {
+			seen[term] = true
+			list = append(list, term)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// ParseTermList splits the legacy string form.
+func ParseTermList(raw string) TermList {
+	return NewTermList(strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == '|' }))
+}
+
+// Unknown returns the terms missing from vocabulary.
+func (l TermList) Unknown(vocabulary []string) []string {
+	known := make(map[string]bool, len(vocabulary))
+	for _, term := range vocabulary {
+		known[term] = true
+	}
+	var unknown []string
+	for _, term := range l {
+		if !known[term] {
+			unknown = append(unknown, term)
+		}
+	}
+	return unknown
+}
+
+func (l *TermList) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*l = ParseTermList(raw)
+		return nil
+	}
+	var terms []string
+	if err := json.Unmarshal(data, &terms); err != nil {
+		return fmt.Errorf("expected a list of terms: %w", err)
+	}
+	*l = NewTermList(terms)
+	return nil
+}
+
+func (l *TermList) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*l = nil
+		return nil
+	case bsontype.String:
+		*l = ParseTermList(value.StringValue())
+		return nil
+	}
+	var terms []string
+	if err := value.Unmarshal(&terms); err != nil {
+		return err
+	}
+	*l = terms
+	return nil
+}
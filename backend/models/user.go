@@ -1,15 +1,54 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	RoleUser      = "user"
+	RoleAgent     = "agent"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var Roles = []string{RoleUser, RoleAgent, RoleModerator, RoleAdmin}
+
+// CanModerate reports whether role may verify listings. Admins can do
+// everything moderators can.
+func CanModerate(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
+
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	UserID    string             `bson:"userID" json:"userID"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"password,omitempty"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID   string             `bson:"userID" json:"userID" validate:"required,max=64"`
+	Email    string             `bson:"email" json:"email" validate:"required,email,max=254"`
+	Password string             `bson:"password" json:"password,omitempty" validate:"required"`
+	// Role is empty for users registered before roles existed, which
+	// counts as RoleUser.
+	Role      string    `bson:"role,omitempty" json:"role,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	// TokenVersion is embedded in every token issued to the user. Raising it
+	// revokes all tokens issued before.
+	TokenVersion int `bson:"tokenVersion" json:"-"`
+
+	// Verified is set once the user proves they own Email.
+	Verified bool `bson:"verified" json:"verified"`
+}
+
+// NormalizeEmail returns the form emails are stored and looked up in, so
+// addresses differing only in case belong to the same account.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// EffectiveRole returns the user's role, treating a missing one as RoleUser.
+func (u *User) EffectiveRole() string {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
 }
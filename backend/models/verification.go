@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Listings move between these verification statuses. IsVerified is true
+// exactly when the status is VerificationApproved.
+const (
+	VerificationPending  = "pending"
+	VerificationApproved = "approved"
+	VerificationRejected = "rejected"
+)
+
+// VerificationEvent records a change of a listing's verification status and
+// who made it.
+type VerificationEvent struct {
+	Status string    `bson:"status" json:"status"`
+	Reason string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	By     string    `bson:"by" json:"by"`
+	At     time.Time `bson:"at" json:"at"`
+}
@@ -1,35 +1,87 @@
 package routes
 
 import (
+	"github.com/dcode-github/property_lisitng_system/backend/cache"
 	"github.com/dcode-github/property_lisitng_system/backend/controllers"
+	"github.com/dcode-github/property_lisitng_system/backend/mail"
 	"github.com/dcode-github/property_lisitng_system/backend/middleware"
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"github.com/dcode-github/property_lisitng_system/backend/store"
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
 	"github.com/gorilla/mux"
-	"github.com/redis/go-redis/v9"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func Routes(router *mux.Router, client *mongo.Client, redisClient *redis.Client) {
+// Config carries the services and settings the handlers are built with.
+type Config struct {
+	Limiter      *middleware.RateLimiter
+	Lifetimes    utils.TokenLifetimes
+	AdminUserIDs []string
+	Mailer       mail.Mailer
+}
+
+func Routes(router *mux.Router, stores *store.Store, appCache cache.Cache, cfg Config) {
+	limiter, lifetimes := cfg.Limiter, cfg.Lifetimes
+
 	// Auth routes
-	router.HandleFunc("/register", controllers.RegisterUser()).Methods("POST")
-	router.HandleFunc("/login", controllers.LoginUser()).Methods("POST")
+	router.HandleFunc("/register", controllers.RegisterUser(stores.Users, stores.OneTimeTokens, cfg.Mailer, lifetimes, cfg.AdminUserIDs)).Methods("POST")
+	router.HandleFunc("/login", controllers.LoginUser(stores.Users, stores.RefreshTokens, lifetimes)).Methods("POST")
+	router.HandleFunc("/refresh", controllers.RefreshAccessToken(stores.Users, stores.RefreshTokens, lifetimes)).Methods("POST")
+
+	// Password reset and email verification routes. They send mail or
+	// accept mailed tokens, so they share the anonymous rate limit.
+	router.Handle("/password/forgot", limiter.Middleware(controllers.ForgotPassword(stores.Users, stores.OneTimeTokens, cfg.Mailer, lifetimes))).Methods("POST")
+	router.Handle("/password/reset", limiter.Middleware(controllers.ResetPassword(stores.Users, stores.OneTimeTokens, stores.RefreshTokens, stores.Revocations))).Methods("POST")
+	router.Handle("/email/verify", limiter.Middleware(controllers.VerifyEmail(stores.Users, stores.OneTimeTokens))).Methods("POST")
+
+	// Read-only routes open to anonymous callers. They are registered first
+	// so the GETs are not caught by the authenticated subrouter.
+	public := router.PathPrefix("/api").Subrouter()
+	public.Use(middleware.OptionalAuthMiddleware(stores.Revocations), limiter.Middleware)
 
 	// Routes that require authentication
 	authenticated := router.PathPrefix("/api").Subrouter()
-	authenticated.Use(middleware.AuthMiddleware)
+	authenticated.Use(middleware.AuthMiddleware(stores.Revocations), limiter.Middleware)
+
+	matcher := controllers.NewSavedSearchMatcher(stores.Properties, stores.SavedSearches, stores.Notifications)
+
+	// Session routes
+	authenticated.HandleFunc("/logout", controllers.Logout(stores.RefreshTokens, stores.Revocations)).Methods("POST")
+	authenticated.HandleFunc("/logout-all", controllers.LogoutAll(stores.RefreshTokens, stores.Revocations)).Methods("POST")
+	authenticated.HandleFunc("/email/verification", controllers.ResendEmailVerification(stores.Users, stores.OneTimeTokens, cfg.Mailer, lifetimes)).Methods("POST")
 
 	// Property routes
-	authenticated.HandleFunc("/properties", controllers.CreateProperty(redisClient)).Methods("POST")
-	authenticated.HandleFunc("/properties", controllers.GetAllProperties(redisClient)).Methods("GET")
-	// authenticated.HandleFunc("/properties/{id}", controllers.GetPropertyByID()).Methods("GET")
-	authenticated.HandleFunc("/properties/{id}", controllers.UpdateProperty(redisClient)).Methods("PUT")
-	authenticated.HandleFunc("/properties/{id}", controllers.DeleteProperty(redisClient)).Methods("DELETE")
+	public.HandleFunc("/properties", controllers.GetAllProperties(stores.Properties, stores.Favorites, appCache)).Methods("GET")
+	public.HandleFunc("/properties/search", controllers.SearchProperties(stores.Properties, stores.Favorites, appCache)).Methods("POST")
+	public.HandleFunc("/properties/{id}", controllers.GetPropertyByID(stores.Properties, stores.Favorites, stores.Recommendations, appCache)).Methods("GET")
+	public.HandleFunc("/vocabulary", controllers.GetVocabulary()).Methods("GET")
+	authenticated.HandleFunc("/properties", controllers.CreateProperty(stores.Properties, appCache, matcher)).Methods("POST")
+	authenticated.HandleFunc("/properties/{id}", controllers.UpdateProperty(stores.Properties, appCache, matcher)).Methods("PUT")
+	authenticated.HandleFunc("/properties/{id}", controllers.DeleteProperty(stores.Properties, stores.Favorites, stores.Recommendations, appCache)).Methods("DELETE")
+	authenticated.HandleFunc("/properties/{id}/verification", controllers.GetPropertyVerification(stores.Properties)).Methods("GET")
+
+	// Moderation routes
+	moderators := middleware.RequireRole(models.RoleModerator, models.RoleAdmin)
+	authenticated.Handle("/properties/{id}/approve", moderators(controllers.ReviewProperty(stores.Properties, appCache, models.VerificationApproved))).Methods("POST")
+	authenticated.Handle("/properties/{id}/reject", moderators(controllers.ReviewProperty(stores.Properties, appCache, models.VerificationRejected))).Methods("POST")
 
 	// Favorites routes
-	authenticated.HandleFunc("/favorites", controllers.AddFavorite(redisClient)).Methods("POST")
-	authenticated.HandleFunc("/favorites", controllers.GetFavorites(redisClient)).Methods("GET")
-	authenticated.HandleFunc("/favorites/{id}", controllers.DeleteFavorite(redisClient)).Methods("DELETE")
+	authenticated.HandleFunc("/favorites", controllers.AddFavorite(stores.Favorites, appCache)).Methods("POST")
+	authenticated.HandleFunc("/favorites", controllers.GetFavorites(stores.Favorites, appCache)).Methods("GET")
+	authenticated.HandleFunc("/favorites/{id}", controllers.DeleteFavorite(stores.Favorites, appCache)).Methods("DELETE")
 
 	// Recommendations routes
-	authenticated.HandleFunc("/recommend", controllers.RecommendProperty(redisClient)).Methods("POST")
-	authenticated.HandleFunc("/recommendations", controllers.GetRecommendations(redisClient)).Methods("GET")
+	authenticated.HandleFunc("/recommend", controllers.RecommendProperty(stores.Users, stores.Recommendations, appCache)).Methods("POST")
+	authenticated.HandleFunc("/recommendations", controllers.GetRecommendations(stores.Recommendations, appCache)).Methods("GET")
+
+	// Saved search routes
+	authenticated.HandleFunc("/saved-searches", controllers.CreateSavedSearch(stores.SavedSearches)).Methods("POST")
+	authenticated.HandleFunc("/saved-searches", controllers.GetSavedSearches(stores.SavedSearches)).Methods("GET")
+	authenticated.HandleFunc("/saved-searches/{id}", controllers.DeleteSavedSearch(stores.SavedSearches, stores.Notifications)).Methods("DELETE")
+	authenticated.HandleFunc("/notifications", controllers.GetNotifications(stores.Notifications)).Methods("GET")
+
+	// Admin routes
+	authenticated.Handle("/users/{userID}/role", middleware.RequireRole(models.RoleAdmin)(controllers.SetUserRole(stores.Users, stores.RefreshTokens, stores.Revocations))).Methods("PUT")
+
+	// Cache routes
+	authenticated.HandleFunc("/cache/stats", controllers.GetCacheStats(appCache)).Methods("GET")
 }
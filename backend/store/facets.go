@@ -0,0 +1,41 @@
+package store
+
+import "sort"
+
+// rangeBuckets returns the empty buckets for boundaries, including the
+// open-ended overflow bucket.
+func rangeBuckets(boundaries []float64) []FacetBucket {
+	buckets := make([]FacetBucket, 0, len(boundaries))
+	for i := range boundaries {
+		min := boundaries[i]
+		bucket := FacetBucket{Min: &min}
+		if i+1 < len(boundaries) {
+			max := boundaries[i+1]
+			bucket.Max = &max
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// bucketIndex finds the bucket a value falls into, or -1 when it is below the
+// first boundary. Values past the last boundary fall into the overflow
+// bucket.
+func bucketIndex(boundaries []float64, value float64) int {
+	return sort.Search(len(boundaries), func(i int) bool { return boundaries[i] > value }) - 1
+}
+
+func sortTermBuckets(buckets []FacetBucket, maxTerms int) []FacetBucket {
+	// Ties are broken by value like the $sort on count and _id in Mongo, so
+	// both stores keep the same terms when MaxTerms cuts the list.
+	sort.SliceStable(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return compareSortValues(buckets[i].Value, buckets[j].Value) < 0
+	})
+	if maxTerms > 0 && len(buckets) > maxTerms {
+		buckets = buckets[:maxTerms]
+	}
+	return buckets
+}
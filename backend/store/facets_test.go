@@ -0,0 +1,40 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortTermBuckets(t *testing.T) {
+	tests := []struct {
+		name     string
+		buckets  []FacetBucket
+		maxTerms int
+		want     []FacetBucket
+	}{
+		{name: "count descending",
+			buckets: []FacetBucket{{Value: "Delhi", Count: 1}, {Value: "Pune", Count: 3}},
+			want:    []FacetBucket{{Value: "Pune", Count: 3}, {Value: "Delhi", Count: 1}}},
+		{name: "ties by value ascending",
+			buckets: []FacetBucket{{Value: "Pune", Count: 2}, {Value: "Agra", Count: 2}, {Value: "Delhi", Count: 5}},
+			want:    []FacetBucket{{Value: "Delhi", Count: 5}, {Value: "Agra", Count: 2}, {Value: "Pune", Count: 2}}},
+		{name: "null sorts first among ties",
+			buckets: []FacetBucket{{Value: "Pune", Count: 1}, {Value: nil, Count: 1}},
+			want:    []FacetBucket{{Value: nil, Count: 1}, {Value: "Pune", Count: 1}}},
+		{name: "numeric ties",
+			buckets: []FacetBucket{{Value: int32(3), Count: 1}, {Value: 2.0, Count: 1}},
+			want:    []FacetBucket{{Value: 2.0, Count: 1}, {Value: int32(3), Count: 1}}},
+		{name: "max terms keeps the lowest tied values",
+			buckets:  []FacetBucket{{Value: "Pune", Count: 1}, {Value: "Delhi", Count: 1}, {Value: "Agra", Count: 1}},
+			maxTerms: 2,
+			want:     []FacetBucket{{Value: "Agra", Count: 1}, {Value: "Delhi", Count: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortTermBuckets(tt.buckets, tt.maxTerms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortTermBuckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryDB holds every collection of the in-memory backend behind a single
+// lock so that joins such as favorites -> properties see a consistent view.
+type memoryDB struct {
+	mu              sync.RWMutex
+	users           []models.User
+	properties      []models.Property
+	favorites       []models.Favorite
+	recommendations []models.Recommendation
+	savedSearches   []models.SavedSearch
+	notifications   []models.Notification
+	refreshTokens   []models.RefreshToken
+	revokedTokens   map[string]time.Time
+	oneTimeTokens   []models.OneTimeToken
+}
+
+func NewMemoryStore() *Store {
+	db := &memoryDB{revokedTokens: make(map[string]time.Time)}
+	return &Store{
+		Properties:      &memoryPropertyStore{db: db},
+		Users:           &memoryUserStore{db: db},
+		Favorites:       &memoryFavoriteStore{db: db},
+		Recommendations: &memoryRecommendationStore{db: db},
+		SavedSearches:   &memorySavedSearchStore{db: db},
+		Notifications:   &memoryNotificationStore{db: db},
+		RefreshTokens:   &memoryRefreshTokenStore{db: db},
+		Revocations:     &memoryRevocationStore{db: db},
+		OneTimeTokens:   &memoryOneTimeTokenStore{db: db},
+	}
+}
+
+func (db *memoryDB) propertyIndex(id primitive.ObjectID) int {
+	for i := range db.properties {
+		if db.properties[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryFavoriteStore struct {
+	db *memoryDB
+}
+
+func (s *memoryFavoriteStore) Add(ctx context.Context, favorite *models.Favorite) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored := *favorite
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	s.db.favorites = append(s.db.favorites, stored)
+	return nil
+}
+
+func (s *memoryFavoriteStore) Exists(ctx context.Context, userID string, propertyID primitive.ObjectID) (bool, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	for _, fav := range s.db.favorites {
+		if fav.UserID == userID && fav.PropertyID == propertyID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryFavoriteStore) Remove(ctx context.Context, userID string, propertyID primitive.ObjectID) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i, fav := range s.db.favorites {
+		if fav.UserID == userID && fav.PropertyID == propertyID {
+			s.db.favorites = append(s.db.favorites[:i], s.db.favorites[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryFavoriteStore) ListPropertyIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	propertyIDs := []primitive.ObjectID{}
+	for _, fav := range s.db.favorites {
+		if fav.UserID == userID {
+			propertyIDs = append(propertyIDs, fav.PropertyID)
+		}
+	}
+	return propertyIDs, nil
+}
+
+func (s *memoryFavoriteStore) ListProperties(ctx context.Context, userID string, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return queryProperties(s.favoriteProperties(userID), filter, opts)
+}
+
+func (s *memoryFavoriteStore) CountProperties(ctx context.Context, userID string, filter bson.M) (int64, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return countProperties(s.favoriteProperties(userID), filter)
+}
+
+func (s *memoryFavoriteStore) favoriteProperties(userID string) []models.Property {
+	var properties []models.Property
+	for _, fav := range s.db.favorites {
+		if fav.UserID != userID {
+			continue
+		}
+		i := s.db.propertyIndex(fav.PropertyID)
+		if i < 0 {
+			continue
+		}
+		property := s.db.properties[i]
+		property.IsFavorite = true
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+func (s *memoryFavoriteStore) DeleteByProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	kept := s.db.favorites[:0]
+	for _, fav := range s.db.favorites {
+		if fav.PropertyID != propertyID {
+			kept = append(kept, fav)
+		}
+	}
+	s.db.favorites = kept
+	return nil
+}
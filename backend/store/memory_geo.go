@@ -0,0 +1,92 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/dcode-github/property_lisitng_system/backend/utils"
+)
+
+// matchGeoWithin supports the $centerSphere, $box and GeoJSON $geometry
+// polygon shapes of $geoWithin for point fields.
+func matchGeoWithin(value interface{}, arg interface{}) (bool, error) {
+	shape, ok := toFilter(arg)
+	if !ok {
+		return false, fmt.Errorf("$geoWithin expects a document, got %T", arg)
+	}
+	lng, lat, isPoint := pointCoordinates(value)
+
+	if sphere, ok := shape["$centerSphere"]; ok {
+		spec := toList(sphere)
+		if len(spec) != 2 {
+			return false, fmt.Errorf("$centerSphere expects [[lng, lat], radius]")
+		}
+		centerLng, centerLat, ok := coordinatePair(spec[0])
+		radius, isNumber := toFloat(spec[1])
+		if !ok || !isNumber {
+			return false, fmt.Errorf("$centerSphere expects [[lng, lat], radius]")
+		}
+		return isPoint && utils.HaversineKm(lat, lng, centerLat, centerLng) <= radius*utils.EarthRadiusKm, nil
+	}
+
+	if box, ok := shape["$box"]; ok {
+		corners := toList(box)
+		if len(corners) != 2 {
+			return false, fmt.Errorf("$box expects [[minLng, minLat], [maxLng, maxLat]]")
+		}
+		minLng, minLat, okMin := coordinatePair(corners[0])
+		maxLng, maxLat, okMax := coordinatePair(corners[1])
+		if !okMin || !okMax {
+			return false, fmt.Errorf("$box expects [[minLng, minLat], [maxLng, maxLat]]")
+		}
+		return isPoint && lng >= minLng && lng <= maxLng && lat >= minLat && lat <= maxLat, nil
+	}
+
+	if geometry, ok := shape["$geometry"]; ok {
+		g, ok := toFilter(geometry)
+		if !ok || g["type"] != "Polygon" {
+			return false, fmt.Errorf("$geometry only supports Polygon")
+		}
+		rings := toList(g["coordinates"])
+		if len(rings) == 0 {
+			return false, fmt.Errorf("$geometry polygon has no rings")
+		}
+		return isPoint && pointInRing(lng, lat, toList(rings[0])), nil
+	}
+
+	return false, fmt.Errorf("unsupported $geoWithin shape")
+}
+
+func pointCoordinates(value interface{}) (float64, float64, bool) {
+	point, ok := toFilter(value)
+	if !ok || point["type"] != "Point" {
+		return 0, 0, false
+	}
+	return coordinatePair(point["coordinates"])
+}
+
+func coordinatePair(v interface{}) (float64, float64, bool) {
+	pair := toList(v)
+	if len(pair) != 2 {
+		return 0, 0, false
+	}
+	lng, lngOK := toFloat(pair[0])
+	lat, latOK := toFloat(pair[1])
+	return lng, lat, lngOK && latOK
+}
+
+// pointInRing is a planar ray casting test, adequate for small
+// viewport-sized polygons.
+func pointInRing(lng, lat float64, ring []interface{}) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi, okI := coordinatePair(ring[i])
+		xj, yj, okJ := coordinatePair(ring[j])
+		if !okI || !okJ {
+			return false
+		}
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMatchGeoWithin(t *testing.T) {
+	// Pune, roughly.
+	point := bson.M{"type": "Point", "coordinates": bson.A{73.85, 18.52}}
+	box := func(minLng, minLat, maxLng, maxLat float64) bson.M {
+		return bson.M{"$box": bson.A{bson.A{minLng, minLat}, bson.A{maxLng, maxLat}}}
+	}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		shape   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{name: "inside box", value: point, shape: box(73, 18, 74, 19), want: true},
+		{name: "outside box", value: point, shape: box(72, 18, 73, 19), want: false},
+		{name: "box edges are inclusive", value: point, shape: box(73.85, 18.52, 74, 19), want: true},
+		{name: "box with int corners", value: point, shape: bson.M{"$box": bson.A{bson.A{73, 18}, bson.A{74, 19}}}, want: true},
+		{name: "missing point in box", value: nil, shape: box(73, 18, 74, 19), want: false},
+		{name: "box with one corner", value: point, shape: bson.M{"$box": bson.A{bson.A{73, 18}}}, wantErr: true},
+		{name: "box with bad corner", value: point, shape: bson.M{"$box": bson.A{bson.A{73}, bson.A{74, 19}}}, wantErr: true},
+		{name: "inside sphere", value: point, shape: bson.M{"$centerSphere": bson.A{bson.A{73.86, 18.53}, 5 / 6371.0}}, want: true},
+		{name: "outside sphere", value: point, shape: bson.M{"$centerSphere": bson.A{bson.A{72.87, 19.07}, 5 / 6371.0}}, want: false},
+		{name: "inside polygon", value: point, shape: bson.M{"$geometry": bson.M{"type": "Polygon", "coordinates": bson.A{bson.A{
+			bson.A{73.0, 18.0}, bson.A{74.0, 18.0}, bson.A{74.0, 19.0}, bson.A{73.0, 19.0}, bson.A{73.0, 18.0},
+		}}}}, want: true},
+		{name: "unsupported shape", value: point, shape: bson.M{"$center": bson.A{bson.A{73, 18}, 1}}, wantErr: true},
+		{name: "not a document", value: point, shape: "box", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchGeoWithin(tt.value, tt.shape)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchGeoWithin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("matchGeoWithin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
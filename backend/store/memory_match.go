@@ -0,0 +1,364 @@
+package store
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toDocument converts a model into the same field layout MongoDB would store,
+// so filters written for Mongo can be evaluated in memory.
+func toDocument(v interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func fromDocument(doc bson.M, v interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, v)
+}
+
+// matchDocument evaluates the subset of the MongoDB query language used by the
+// handlers against doc.
+func matchDocument(doc bson.M, filter bson.M) (bool, error) {
+	for key, cond := range filter {
+		var (
+			ok  bool
+			err error
+		)
+		switch key {
+		case "$and":
+			ok, err = matchAll(doc, cond)
+		case "$or":
+			ok, err = matchAny(doc, cond)
+		case "$nor":
+			ok, err = matchAny(doc, cond)
+			ok = !ok
+		case "$text":
+			ok, err = matchText(doc, cond)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("unsupported query operator %s", key)
+			}
+			ok, err = matchField(lookupField(doc, key), cond)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchAll(doc bson.M, cond interface{}) (bool, error) {
+	filters, err := toFilterList(cond)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range filters {
+		ok, err := matchDocument(doc, f)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchAny(doc bson.M, cond interface{}) (bool, error) {
+	filters, err := toFilterList(cond)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range filters {
+		ok, err := matchDocument(doc, f)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func toFilterList(cond interface{}) ([]bson.M, error) {
+	var filters []bson.M
+	for _, item := range toList(cond) {
+		f, ok := toFilter(item)
+		if !ok {
+			return nil, fmt.Errorf("expected query document, got %T", item)
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+func toFilter(v interface{}) (bson.M, bool) {
+	switch m := v.(type) {
+	case bson.M:
+		return m, true
+	case map[string]interface{}:
+		return bson.M(m), true
+	case bson.D:
+		return m.Map(), true
+	}
+	return nil, false
+}
+
+func lookupField(doc bson.M, path string) interface{} {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := toFilter(current)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+func isOperatorDocument(m bson.M) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+func matchField(value interface{}, cond interface{}) (bool, error) {
+	if ops, ok := toFilter(cond); ok && isOperatorDocument(ops) {
+		for op, arg := range ops {
+			if op == "$options" {
+				continue
+			}
+			if pattern, isString := arg.(string); op == "$regex" && isString {
+				options, _ := ops["$options"].(string)
+				arg = primitive.Regex{Pattern: pattern, Options: options}
+			}
+			ok, err := matchOperator(value, op, arg)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return matchEquals(value, cond)
+}
+
+func matchOperator(value interface{}, op string, arg interface{}) (bool, error) {
+	switch op {
+	case "$eq":
+		return matchEquals(value, arg)
+	case "$ne":
+		ok, err := matchEquals(value, arg)
+		return !ok, err
+	case "$gt", "$gte", "$lt", "$lte":
+		return anyElement(value, func(v interface{}) (bool, error) {
+			cmp, comparable := compareValues(v, arg)
+			if !comparable {
+				return false, nil
+			}
+			switch op {
+			case "$gt":
+				return cmp > 0, nil
+			case "$gte":
+				return cmp >= 0, nil
+			case "$lt":
+				return cmp < 0, nil
+			default:
+				return cmp <= 0, nil
+			}
+		})
+	case "$in":
+		return matchIn(value, arg)
+	case "$nin":
+		ok, err := matchIn(value, arg)
+		return !ok, err
+	case "$all":
+		// Like MongoDB, an empty $all list matches no documents.
+		wants := toList(arg)
+		if len(wants) == 0 {
+			return false, nil
+		}
+		for _, want := range wants {
+			ok, err := matchEquals(value, want)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case "$regex":
+		return matchEquals(value, arg)
+	case "$geoWithin":
+		return matchGeoWithin(value, arg)
+	case "$exists":
+		want, _ := arg.(bool)
+		return (value != nil) == want, nil
+	}
+	return false, fmt.Errorf("unsupported field operator %s", op)
+}
+
+func matchIn(value interface{}, arg interface{}) (bool, error) {
+	for _, candidate := range toList(arg) {
+		ok, err := matchEquals(value, candidate)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchEquals follows Mongo semantics where a condition on an array field
+// matches if any element satisfies it.
+func matchEquals(value interface{}, want interface{}) (bool, error) {
+	if re, ok := want.(primitive.Regex); ok {
+		pattern := re.Pattern
+		if strings.Contains(re.Options, "i") {
+			pattern = "(?i)" + pattern
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		return anyElement(value, func(v interface{}) (bool, error) {
+			s, isString := v.(string)
+			return isString && compiled.MatchString(s), nil
+		})
+	}
+	return anyElement(value, func(v interface{}) (bool, error) {
+		cmp, comparable := compareValues(v, want)
+		return comparable && cmp == 0, nil
+	})
+}
+
+func anyElement(value interface{}, fn func(interface{}) (bool, error)) (bool, error) {
+	if isList(value) {
+		for _, v := range toList(value) {
+			ok, err := fn(v)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+	return fn(value)
+}
+
+func isList(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if _, isBytes := v.([]byte); isBytes {
+		return false
+	}
+	kind := reflect.TypeOf(v).Kind()
+	return kind == reflect.Slice || kind == reflect.Array && reflect.TypeOf(v) != reflect.TypeOf(primitive.ObjectID{})
+}
+
+func toList(v interface{}) []interface{} {
+	if !isList(v) {
+		return []interface{}{v}
+	}
+	rv := reflect.ValueOf(v)
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list
+}
+
+// compareValues orders two scalar values of compatible types. The second
+// result is false when the values cannot be compared.
+func compareValues(a, b interface{}) (int, bool) {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return compareOrdered(af, bf), true
+		}
+		return 0, false
+	}
+	if at, ok := toTime(a); ok {
+		if bt, ok := toTime(b); ok {
+			return compareOrdered(at.UnixMilli(), bt.UnixMilli()), true
+		}
+		return 0, false
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), true
+		}
+	case bool:
+		if bv, ok := b.(bool); ok && av == bv {
+			return 0, true
+		} else if ok {
+			if !av {
+				return -1, true
+			}
+			return 1, true
+		}
+	case primitive.ObjectID:
+		if bv, ok := b.(primitive.ObjectID); ok {
+			return strings.Compare(av.Hex(), bv.Hex()), true
+		}
+	case nil:
+		return 0, b == nil
+	}
+	return 0, false
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case primitive.DateTime:
+		return t.Time(), true
+	}
+	return time.Time{}, false
+}
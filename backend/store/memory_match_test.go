@@ -0,0 +1,125 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatchDocument(t *testing.T) {
+	id := primitive.NewObjectID()
+	listed := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	doc := bson.M{
+		"_id":        id,
+		"title":      "Sunny Flat",
+		"price":      int64(1500),
+		"bedrooms":   int32(2),
+		"city":       "Pune",
+		"isVerified": true,
+		"amenities":  bson.A{"gym", "pool"},
+		"listedOn":   primitive.NewDateTimeFromTime(listed),
+		"owner":      bson.M{"name": "Asha"},
+		"notes":      nil,
+	}
+
+	tests := []struct {
+		name    string
+		filter  bson.M
+		want    bool
+		wantErr bool
+	}{
+		{name: "empty filter", filter: bson.M{}, want: true},
+		{name: "equal string", filter: bson.M{"city": "Pune"}, want: true},
+		{name: "equal string mismatch", filter: bson.M{"city": "Delhi"}, want: false},
+		{name: "equal across numeric types", filter: bson.M{"price": 1500.0}, want: true},
+		{name: "equal object id", filter: bson.M{"_id": id}, want: true},
+		{name: "equal nested path", filter: bson.M{"owner.name": "Asha"}, want: true},
+		{name: "equal array element", filter: bson.M{"amenities": "gym"}, want: true},
+		{name: "equal array element missing", filter: bson.M{"amenities": "lift"}, want: false},
+		{name: "null matches missing field", filter: bson.M{"parking": nil}, want: true},
+		{name: "null matches null field", filter: bson.M{"notes": nil}, want: true},
+		{name: "eq", filter: bson.M{"bedrooms": bson.M{"$eq": 2}}, want: true},
+		{name: "ne", filter: bson.M{"bedrooms": bson.M{"$ne": 2}}, want: false},
+		{name: "gt", filter: bson.M{"price": bson.M{"$gt": 1000}}, want: true},
+		{name: "gte boundary", filter: bson.M{"price": bson.M{"$gte": 1500}}, want: true},
+		{name: "lt boundary", filter: bson.M{"price": bson.M{"$lt": 1500}}, want: false},
+		{name: "range", filter: bson.M{"price": bson.M{"$gte": 1000, "$lte": 2000}}, want: true},
+		{name: "range outside", filter: bson.M{"price": bson.M{"$gte": 2000, "$lte": 3000}}, want: false},
+		{name: "range on missing field", filter: bson.M{"parking": bson.M{"$gt": 0}}, want: false},
+		{name: "range across types", filter: bson.M{"price": bson.M{"$gt": "100"}}, want: false},
+		{name: "date range", filter: bson.M{"listedOn": bson.M{"$gte": listed.AddDate(0, 0, -1)}}, want: true},
+		{name: "date range excludes", filter: bson.M{"listedOn": bson.M{"$gt": listed}}, want: false},
+		{name: "bool", filter: bson.M{"isVerified": true}, want: true},
+		{name: "in", filter: bson.M{"city": bson.M{"$in": bson.A{"Delhi", "Pune"}}}, want: true},
+		{name: "in on array", filter: bson.M{"amenities": bson.M{"$in": []string{"lift", "pool"}}}, want: true},
+		{name: "nin", filter: bson.M{"city": bson.M{"$nin": bson.A{"Delhi", "Pune"}}}, want: false},
+		{name: "all", filter: bson.M{"amenities": bson.M{"$all": bson.A{"gym", "pool"}}}, want: true},
+		{name: "all missing one", filter: bson.M{"amenities": bson.M{"$all": bson.A{"gym", "lift"}}}, want: false},
+		{name: "all empty", filter: bson.M{"amenities": bson.M{"$all": bson.A{}}}, want: false},
+		{name: "regex", filter: bson.M{"title": bson.M{"$regex": "^sunny", "$options": "i"}}, want: true},
+		{name: "regex case sensitive", filter: bson.M{"title": bson.M{"$regex": "^sunny"}}, want: false},
+		{name: "regex value", filter: bson.M{"title": primitive.Regex{Pattern: "flat", Options: "i"}}, want: true},
+		{name: "exists", filter: bson.M{"title": bson.M{"$exists": true}}, want: true},
+		{name: "not exists", filter: bson.M{"parking": bson.M{"$exists": false}}, want: true},
+		{name: "and", filter: bson.M{"$and": bson.A{bson.M{"city": "Pune"}, bson.M{"bedrooms": 2}}}, want: true},
+		{name: "and fails", filter: bson.M{"$and": bson.A{bson.M{"city": "Pune"}, bson.M{"bedrooms": 3}}}, want: false},
+		{name: "or", filter: bson.M{"$or": bson.A{bson.M{"city": "Delhi"}, bson.M{"bedrooms": 2}}}, want: true},
+		{name: "nor", filter: bson.M{"$nor": bson.A{bson.M{"city": "Delhi"}, bson.M{"bedrooms": 2}}}, want: false},
+		{name: "bson d clause", filter: bson.M{"$and": bson.A{bson.D{{Key: "city", Value: "Pune"}}}}, want: true},
+		{name: "invalid regex", filter: bson.M{"title": bson.M{"$regex": "("}}, wantErr: true},
+		{name: "unsupported top level operator", filter: bson.M{"$where": "true"}, wantErr: true},
+		{name: "unsupported field operator", filter: bson.M{"price": bson.M{"$mod": bson.A{2, 0}}}, wantErr: true},
+		{name: "non document clause", filter: bson.M{"$and": bson.A{"city"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchDocument(doc, tt.filter)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("matchDocument(%v) = %v, want error", tt.filter, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("matchDocument(%v) error: %v", tt.filter, err)
+			}
+			if got != tt.want {
+				t.Errorf("matchDocument(%v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		a, b           interface{}
+		want           int
+		wantComparable bool
+	}{
+		{name: "ints", a: 1, b: int64(2), want: -1, wantComparable: true},
+		{name: "int and float", a: int32(3), b: 3.0, want: 0, wantComparable: true},
+		{name: "strings", a: "b", b: "a", want: 1, wantComparable: true},
+		{name: "false before true", a: false, b: true, want: -1, wantComparable: true},
+		{name: "time and datetime", a: now, b: primitive.NewDateTimeFromTime(now), want: 0, wantComparable: true},
+		{name: "nils", a: nil, b: nil, want: 0, wantComparable: true},
+		{name: "nil and value", a: nil, b: 1, wantComparable: false},
+		{name: "number and string", a: 1, b: "1", wantComparable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, comparable := compareValues(tt.a, tt.b)
+			if comparable != tt.wantComparable {
+				t.Fatalf("compareValues(%v, %v) comparable = %v, want %v", tt.a, tt.b, comparable, tt.wantComparable)
+			}
+			if comparable && got != tt.want {
+				t.Errorf("compareValues(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
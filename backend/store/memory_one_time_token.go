@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryOneTimeTokenStore struct {
+	db *memoryDB
+}
+
+func (s *memoryOneTimeTokenStore) Create(ctx context.Context, token *models.OneTimeToken) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	s.db.oneTimeTokens = append(s.db.oneTimeTokens, *token)
+	return nil
+}
+
+func (s *memoryOneTimeTokenStore) Consume(ctx context.Context, tokenHash, purpose string) (*models.OneTimeToken, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	now := time.Now()
+	for i := range s.db.oneTimeTokens {
+		token := &s.db.oneTimeTokens[i]
+		if token.TokenHash != tokenHash || token.Purpose != purpose || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+			continue
+		}
+		token.UsedAt = &now
+		consumed := *token
+		return &consumed, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memoryOneTimeTokenStore) DeleteByUser(ctx context.Context, userID, purpose string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	// Expired tokens are dropped on the way, as the TTL index does for
+	// MongoDB.
+	now := time.Now()
+	kept := s.db.oneTimeTokens[:0]
+	for _, token := range s.db.oneTimeTokens {
+		if token.ExpiresAt.After(now) && (token.UserID != userID || token.Purpose != purpose) {
+			kept = append(kept, token)
+		}
+	}
+	s.db.oneTimeTokens = kept
+	return nil
+}
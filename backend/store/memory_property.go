@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryPropertyStore struct {
+	db *memoryDB
+}
+
+func (s *memoryPropertyStore) Create(ctx context.Context, property *models.Property) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored := *property
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	s.db.properties = append(s.db.properties, stored)
+	return nil
+}
+
+func (s *memoryPropertyStore) Find(ctx context.Context, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return queryProperties(s.db.properties, filter, opts)
+}
+
+func (s *memoryPropertyStore) Count(ctx context.Context, filter bson.M) (int64, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return countProperties(s.db.properties, filter)
+}
+
+func (s *memoryPropertyStore) Facets(ctx context.Context, filter bson.M, req FacetRequest) (FacetResults, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	matched, err := filterProperties(s.db.properties, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := FacetResults{}
+	for _, field := range req.Terms {
+		counts := make(map[interface{}]int64)
+		var order []interface{}
+		for _, m := range matched {
+			value := lookupField(m.doc, field)
+			if isList(value) {
+				continue
+			}
+			if _, seen := counts[value]; !seen {
+				order = append(order, value)
+			}
+			counts[value]++
+		}
+
+		buckets := make([]FacetBucket, 0, len(order))
+		for _, value := range order {
+			buckets = append(buckets, FacetBucket{Value: value, Count: counts[value]})
+		}
+		results[field] = sortTermBuckets(buckets, req.MaxTerms)
+	}
+
+	for field, boundaries := range req.Ranges {
+		buckets := rangeBuckets(boundaries)
+		for _, m := range matched {
+			value, ok := toFloat(lookupField(m.doc, field))
+			if !ok {
+				continue
+			}
+			if i := bucketIndex(boundaries, value); i >= 0 {
+				buckets[i].Count++
+			}
+		}
+		results[field] = buckets
+	}
+	return results, nil
+}
+
+func (s *memoryPropertyStore) Update(ctx context.Context, id primitive.ObjectID, owner Owner, fields bson.M, verification *models.VerificationEvent) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	i := s.db.propertyIndex(id)
+	if i < 0 || !owner.matches(s.db.properties[i].CreatedBy) {
+		return false, nil
+	}
+
+	doc, err := toDocument(s.db.properties[i])
+	if err != nil {
+		return false, err
+	}
+	for key, value := range fields {
+		doc[key] = value
+	}
+
+	var updated models.Property
+	if err := fromDocument(doc, &updated); err != nil {
+		return false, err
+	}
+	if verification != nil {
+		applyVerification(&updated, *verification)
+	}
+	s.db.properties[i] = updated
+	return true, nil
+}
+
+func (s *memoryPropertyStore) Delete(ctx context.Context, id primitive.ObjectID, owner Owner) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	i := s.db.propertyIndex(id)
+	if i < 0 || !owner.matches(s.db.properties[i].CreatedBy) {
+		return false, nil
+	}
+	s.db.properties = append(s.db.properties[:i], s.db.properties[i+1:]...)
+	return true, nil
+}
+
+func (s *memoryPropertyStore) SetVerification(ctx context.Context, id primitive.ObjectID, event models.VerificationEvent, unlessStatus string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	i := s.db.propertyIndex(id)
+	if i < 0 {
+		return false, nil
+	}
+	property := &s.db.properties[i]
+	if unlessStatus != "" && property.VerificationStatus == unlessStatus {
+		return false, nil
+	}
+	applyVerification(property, event)
+	return true, nil
+}
+
+func applyVerification(property *models.Property, event models.VerificationEvent) {
+	property.VerificationStatus = event.Status
+	property.IsVerified = event.Status == models.VerificationApproved
+	property.VerificationHistory = append(property.VerificationHistory, event)
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type propertyDocument struct {
+	property models.Property
+	doc      bson.M
+}
+
+func filterProperties(properties []models.Property, filter bson.M) ([]propertyDocument, error) {
+	var matched []propertyDocument
+	for _, property := range properties {
+		doc, err := toDocument(property)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := matchDocument(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, propertyDocument{property: property, doc: doc})
+		}
+	}
+	return matched, nil
+}
+
+// queryProperties mirrors a Mongo find: filter, sort, then skip and limit.
+func queryProperties(properties []models.Property, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	matched, err := filterProperties(properties, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.TextScore {
+		terms, _ := textSearch(filter)
+		for i := range matched {
+			score := textScore(matched[i].doc, terms)
+			matched[i].property.Score = score
+			matched[i].doc[TextScoreField] = score
+		}
+	}
+
+	sortFields := opts.sortWithTiebreaker()
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, sf := range sortFields {
+			if sf.Field == TextScoreField && !opts.TextScore {
+				continue
+			}
+			cmp := compareSortValues(lookupField(matched[i].doc, sf.Field), lookupField(matched[j].doc, sf.Field))
+			if cmp == 0 {
+				continue
+			}
+			if sf.Descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	if opts.Skip > 0 {
+		if opts.Skip >= int64(len(matched)) {
+			return nil, nil
+		}
+		matched = matched[opts.Skip:]
+	}
+	if opts.Limit > 0 && opts.Limit < int64(len(matched)) {
+		matched = matched[:opts.Limit]
+	}
+
+	results := make([]models.Property, 0, len(matched))
+	for _, m := range matched {
+		results = append(results, m.property)
+	}
+	return results, nil
+}
+
+// compareSortValues orders like a Mongo sort, where missing and null values
+// come before any other value.
+func compareSortValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	cmp, _ := compareValues(a, b)
+	return cmp
+}
+
+func countProperties(properties []models.Property, filter bson.M) (int64, error) {
+	matched, err := filterProperties(properties, filter)
+	return int64(len(matched)), err
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRecommendationStore struct {
+	db *memoryDB
+}
+
+func (s *memoryRecommendationStore) Create(ctx context.Context, recommendation *models.Recommendation) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored := *recommendation
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	s.db.recommendations = append(s.db.recommendations, stored)
+	return nil
+}
+
+func (s *memoryRecommendationStore) ListProperties(ctx context.Context, toUserID string, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return queryProperties(s.recommendedProperties(toUserID), filter, opts)
+}
+
+func (s *memoryRecommendationStore) CountProperties(ctx context.Context, toUserID string, filter bson.M) (int64, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	return countProperties(s.recommendedProperties(toUserID), filter)
+}
+
+func (s *memoryRecommendationStore) recommendedProperties(toUserID string) []models.Property {
+	var properties []models.Property
+	for _, rec := range s.db.recommendations {
+		if rec.ToUserID != toUserID {
+			continue
+		}
+		i := s.db.propertyIndex(rec.PropertyID)
+		if i < 0 {
+			continue
+		}
+		property := s.db.properties[i]
+		property.RecommendedBy = rec.FromUserID
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+func (s *memoryRecommendationStore) DeleteByProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	kept := s.db.recommendations[:0]
+	for _, rec := range s.db.recommendations {
+		if rec.PropertyID != propertyID {
+			kept = append(kept, rec)
+		}
+	}
+	s.db.recommendations = kept
+	return nil
+}
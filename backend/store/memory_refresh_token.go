@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRefreshTokenStore struct {
+	db *memoryDB
+}
+
+func (s *memoryRefreshTokenStore) Create(ctx context.Context, token *models.RefreshToken) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	// Drop expired tokens, as the TTL index does for MongoDB.
+	now := time.Now()
+	kept := s.db.refreshTokens[:0]
+	for _, existing := range s.db.refreshTokens {
+		if existing.ExpiresAt.After(now) {
+			kept = append(kept, existing)
+		}
+	}
+	s.db.refreshTokens = kept
+
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	s.db.refreshTokens = append(s.db.refreshTokens, *token)
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	for _, token := range s.db.refreshTokens {
+		if token.TokenHash == tokenHash {
+			found := token
+			return &found, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *memoryRefreshTokenStore) MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.refreshTokens {
+		token := &s.db.refreshTokens[i]
+		if token.ID != id {
+			continue
+		}
+		if token.UsedAt != nil || token.Revoked {
+			return false, nil
+		}
+		token.UsedAt = &at
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *memoryRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.refreshTokens {
+		if s.db.refreshTokens[i].FamilyID == familyID {
+			s.db.refreshTokens[i].Revoked = true
+		}
+	}
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) RevokeUser(ctx context.Context, userID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.refreshTokens {
+		if s.db.refreshTokens[i].UserID == userID {
+			s.db.refreshTokens[i].Revoked = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+type memoryRevocationStore struct {
+	db *memoryDB
+}
+
+func (s *memoryRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	if expiresAt.After(s.db.revokedTokens[jti]) {
+		s.db.revokedTokens[jti] = expiresAt
+	}
+	return nil
+}
+
+func (s *memoryRevocationStore) RevokeUserTokens(ctx context.Context, userID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.users {
+		if s.db.users[i].UserID == userID {
+			s.db.users[i].TokenVersion++
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *memoryRevocationStore) IsRevoked(ctx context.Context, jti, userID string, tokenVersion int) (bool, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	if expiresAt, ok := s.db.revokedTokens[jti]; ok && jti != "" && expiresAt.After(time.Now()) {
+		return true, nil
+	}
+	for _, user := range s.db.users {
+		if user.UserID == userID {
+			return tokenVersion < user.TokenVersion, nil
+		}
+	}
+	return true, nil
+}
+
+// pruneLocked drops revoked tokens that have expired anyway. The caller must
+// hold the write lock.
+func (s *memoryRevocationStore) pruneLocked(now time.Time) {
+	for jti, expiresAt := range s.db.revokedTokens {
+		if !expiresAt.After(now) {
+			delete(s.db.revokedTokens, jti)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memorySavedSearchStore struct {
+	db *memoryDB
+}
+
+func (s *memorySavedSearchStore) Create(ctx context.Context, search *models.SavedSearch) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if search.ID.IsZero() {
+		search.ID = primitive.NewObjectID()
+	}
+	s.db.savedSearches = append(s.db.savedSearches, *search)
+	return nil
+}
+
+func (s *memorySavedSearchStore) ListByUser(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	searches := []models.SavedSearch{}
+	for _, search := range s.db.savedSearches {
+		if search.UserID == userID {
+			searches = append(searches, search)
+		}
+	}
+	return searches, nil
+}
+
+func (s *memorySavedSearchStore) ForEach(ctx context.Context, fn func(models.SavedSearch) error) error {
+	// Copy first so fn can use other stores without deadlocking.
+	s.db.mu.RLock()
+	searches := append([]models.SavedSearch(nil), s.db.savedSearches...)
+	s.db.mu.RUnlock()
+
+	for _, search := range searches {
+		if err := fn(search); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memorySavedSearchStore) Delete(ctx context.Context, id primitive.ObjectID, userID string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i, search := range s.db.savedSearches {
+		if search.ID == id && search.UserID == userID {
+			s.db.savedSearches = append(s.db.savedSearches[:i], s.db.savedSearches[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryNotificationStore struct {
+	db *memoryDB
+}
+
+func (s *memoryNotificationStore) Create(ctx context.Context, notification *models.Notification) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, existing := range s.db.notifications {
+		if existing.SavedSearchID == notification.SavedSearchID && existing.PropertyID == notification.PropertyID {
+			return false, nil
+		}
+	}
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	s.db.notifications = append(s.db.notifications, *notification)
+	return true, nil
+}
+
+func (s *memoryNotificationStore) ListByUser(ctx context.Context, userID string, limit int64) ([]models.Notification, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	notifications := []models.Notification{}
+	for _, notification := range s.db.notifications {
+		if notification.UserID == userID {
+			notifications = append(notifications, notification)
+		}
+	}
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	if limit > 0 && int64(len(notifications)) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (s *memoryNotificationStore) DeleteBySavedSearch(ctx context.Context, savedSearchID primitive.ObjectID) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	kept := s.db.notifications[:0]
+	for _, notification := range s.db.notifications {
+		if notification.SavedSearchID != savedSearchID {
+			kept = append(kept, notification)
+		}
+	}
+	s.db.notifications = kept
+	return nil
+}
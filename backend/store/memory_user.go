@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryUserStore struct {
+	db *memoryDB
+}
+
+func (s *memoryUserStore) Create(ctx context.Context, user *models.User) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored := *user
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	s.db.users = append(s.db.users, stored)
+	return nil
+}
+
+func (s *memoryUserStore) FindByUserID(ctx context.Context, userID string) (*models.User, error) {
+	return s.findOne(func(u models.User) bool { return u.UserID == userID })
+}
+
+func (s *memoryUserStore) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	email = models.NormalizeEmail(email)
+	return s.findOne(func(u models.User) bool { return u.Email == email })
+}
+
+func (s *memoryUserStore) SetRole(ctx context.Context, userID, role string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.users {
+		if s.db.users[i].UserID == userID {
+			s.db.users[i].Role = role
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryUserStore) SetPassword(ctx context.Context, userID, hashedPassword string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.users {
+		if s.db.users[i].UserID == userID {
+			s.db.users[i].Password = hashedPassword
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryUserStore) SetVerified(ctx context.Context, userID string) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.users {
+		if s.db.users[i].UserID == userID {
+			s.db.users[i].Verified = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryUserStore) findOne(match func(models.User) bool) (*models.User, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	for _, user := range s.db.users {
+		if match(user) {
+			found := user
+			return &found, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,30 @@
+package store
+
+import "go.mongodb.org/mongo-driver/mongo"
+
+const (
+	usersCollection           = "users"
+	propertiesCollection      = "properties"
+	favoritesCollection       = "favorites"
+	recommendationsCollection = "recommendations"
+	savedSearchesCollection   = "savedSearches"
+	notificationsCollection   = "notifications"
+	refreshTokensCollection   = "refreshTokens"
+	revocationsCollection     = "revocations"
+	oneTimeTokensCollection   = "oneTimeTokens"
+)
+
+func NewMongoStore(db *mongo.Database) *Store {
+	properties := db.Collection(propertiesCollection)
+	return &Store{
+		Properties:      &mongoPropertyStore{collection: properties},
+		Users:           &mongoUserStore{collection: db.Collection(usersCollection)},
+		Favorites:       &mongoFavoriteStore{collection: db.Collection(favoritesCollection), properties: properties},
+		Recommendations: &mongoRecommendationStore{collection: db.Collection(recommendationsCollection), properties: properties},
+		SavedSearches:   &mongoSavedSearchStore{collection: db.Collection(savedSearchesCollection)},
+		Notifications:   &mongoNotificationStore{collection: db.Collection(notificationsCollection)},
+		RefreshTokens:   &mongoRefreshTokenStore{collection: db.Collection(refreshTokensCollection)},
+		Revocations:     &mongoRevocationStore{collection: db.Collection(revocationsCollection), users: db.Collection(usersCollection)},
+		OneTimeTokens:   &mongoOneTimeTokenStore{collection: db.Collection(oneTimeTokensCollection)},
+	}
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoFavoriteStore struct {
+	collection *mongo.Collection
+	properties *mongo.Collection
+}
+
+func (s *mongoFavoriteStore) Add(ctx context.Context, favorite *models.Favorite) error {
+	_, err := s.collection.InsertOne(ctx, favorite)
+	return err
+}
+
+func (s *mongoFavoriteStore) Exists(ctx context.Context, userID string, propertyID primitive.ObjectID) (bool, error) {
+	err := s.collection.FindOne(ctx, bson.M{"userID": userID, "propertyID": propertyID}).Err()
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *mongoFavoriteStore) Remove(ctx context.Context, userID string, propertyID primitive.ObjectID) (bool, error) {
+	res, err := s.collection.DeleteOne(ctx, bson.M{"userID": userID, "propertyID": propertyID})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (s *mongoFavoriteStore) ListPropertyIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"userID": userID}, options.Find().SetProjection(bson.M{"propertyID": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var favs []models.Favorite
+	if err := cursor.All(ctx, &favs); err != nil {
+		return nil, err
+	}
+
+	propertyIDs := make([]primitive.ObjectID, 0, len(favs))
+	for _, fav := range favs {
+		propertyIDs = append(propertyIDs, fav.PropertyID)
+	}
+	return propertyIDs, nil
+}
+
+func (s *mongoFavoriteStore) propertiesPipeline(userID string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userID": userID}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         s.properties.Name(),
+			"localField":   "propertyID",
+			"foreignField": "_id",
+			"as":           "propertyDetails",
+		}}},
+		{{Key: "$unwind", Value: "$propertyDetails"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$propertyDetails"}}},
+	}
+}
+
+func (s *mongoFavoriteStore) ListProperties(ctx context.Context, userID string, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	cursor, err := s.collection.Aggregate(ctx, pagedPipeline(s.propertiesPipeline(userID), filter, opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var properties []models.Property
+	if err := cursor.All(ctx, &properties); err != nil {
+		return nil, err
+	}
+	for i := range properties {
+		properties[i].IsFavorite = true
+	}
+	return properties, nil
+}
+
+func (s *mongoFavoriteStore) CountProperties(ctx context.Context, userID string, filter bson.M) (int64, error) {
+	return countPipeline(ctx, s.collection, s.propertiesPipeline(userID), filter)
+}
+
+func (s *mongoFavoriteStore) DeleteByProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"propertyID": propertyID})
+	return err
+}
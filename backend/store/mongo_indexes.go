@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureMongoIndexes creates the indexes the stores rely on. Creating an
+// index that already exists with the same definition is a no-op.
+func EnsureMongoIndexes(ctx context.Context, db *mongo.Database) error {
+	fields := make([]string, 0, len(propertyTextWeights))
+	for field := range propertyTextWeights {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	textKeys := bson.D{}
+	weights := bson.M{}
+	for _, field := range fields {
+		textKeys = append(textKeys, bson.E{Key: field, Value: "text"})
+		weights[field] = propertyTextWeights[field]
+	}
+
+	_, err := db.Collection(propertiesCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    textKeys,
+			Options: options.Index().SetName("property_text").SetWeights(weights),
+		},
+		{
+			Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+			Options: options.Index().SetName("property_location"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(savedSearchesCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userID", Value: 1}},
+		Options: options.Index().SetName("saved_search_user"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(notificationsCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "savedSearchID", Value: 1}, {Key: "propertyID", Value: 1}},
+			Options: options.Index().SetName("notification_search_property").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("notification_user_created"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(refreshTokensCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetName("refresh_token_hash").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "familyID", Value: 1}},
+			Options: options.Index().SetName("refresh_token_family"),
+		},
+		{
+			Keys:    bson.D{{Key: "userID", Value: 1}},
+			Options: options.Index().SetName("refresh_token_user"),
+		},
+		{
+			// Expired tokens can no longer be exchanged, MongoDB removes them.
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("refresh_token_expiry").SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Every authenticated request looks up the token version of its user.
+	_, err = db.Collection(usersCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userID", Value: 1}},
+		Options: options.Index().SetName("user_id"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(revocationsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetName("revocation_expiry").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection(oneTimeTokensCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetName("one_time_token_hash").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "purpose", Value: 1}},
+			Options: options.Index().SetName("one_time_token_user"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("one_time_token_expiry").SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrateTermLists rewrites amenities and tags stored in the legacy comma or
+// pipe separated string form as normalized arrays. It is safe to run more
+// than once and returns the number of documents changed.
+func MigrateTermLists(ctx context.Context, db *mongo.Database) (int64, error) {
+	collection := db.Collection(propertiesCollection)
+	cursor, err := collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"amenities": bson.M{"$type": "string"}},
+		bson.M{"tags": bson.M{"$type": "string"}},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var migrated int64
+	for cursor.Next(ctx) {
+		// models.TermList decodes the legacy form, so the decoded property
+		// already holds the normalized lists.
+		var property models.Property
+		if err := cursor.Decode(&property); err != nil {
+			return migrated, err
+		}
+		amenities := models.NewTermList(property.Amenities)
+		tags := models.NewTermList(property.Tags)
+		if unknown := amenities.Unknown(models.AmenityVocabulary); len(unknown) > 0 {
+			log.Printf("Property %s keeps amenities outside the vocabulary: %v", property.ID.Hex(), unknown)
+		}
+		if unknown := tags.Unknown(models.TagVocabulary); len(unknown) > 0 {
+			log.Printf("Property %s keeps tags outside the vocabulary: %v", property.ID.Hex(), unknown)
+		}
+
+		_, err := collection.UpdateByID(ctx, property.ID, bson.M{"$set": bson.M{"amenities": amenities, "tags": tags}})
+		if err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+// MigrateVerification sends listings created before the verification
+// workflow, which have no verificationStatus, to pending. Owners could set
+// isVerified themselves back then, so it is cleared until a moderator
+// approves the listing. It is safe to run more than once and returns the
+// number of documents changed.
+func MigrateVerification(ctx context.Context, db *mongo.Database) (int64, error) {
+	res, err := db.Collection(propertiesCollection).UpdateMany(ctx,
+		bson.M{"verificationStatus": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{
+			"$set": bson.M{"verificationStatus": models.VerificationPending, "isVerified": false},
+			"$push": bson.M{"verificationHistory": models.VerificationEvent{
+				Status: models.VerificationPending,
+				Reason: "Listed before verification was required",
+				By:     "migration",
+				At:     time.Now(),
+			}},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// MigrateUsers prepares accounts created before email verification: they
+// are marked verified so they keep receiving recommendations, and their
+// emails are stored in models.NormalizeEmail form. An email that would
+// collide with another account's is left as is and logged. It is safe to run
+// more than once and returns the number of accounts marked verified and of
+// emails normalized.
+func MigrateUsers(ctx context.Context, db *mongo.Database) (verified, normalized int64, err error) {
+	collection := db.Collection(usersCollection)
+	res, err := collection.UpdateMany(ctx,
+		bson.M{"verified": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"verified": true}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	verified = res.ModifiedCount
+
+	cursor, err := collection.Find(ctx, bson.M{"$expr": bson.M{"$ne": bson.A{
+		"$email", bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}},
+	}}})
+	if err != nil {
+		return verified, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			return verified, normalized, err
+		}
+		email := models.NormalizeEmail(user.Email)
+		taken, err := collection.CountDocuments(ctx, bson.M{"email": email, "_id": bson.M{"$ne": user.ID}})
+		if err != nil {
+			return verified, normalized, err
+		}
+		if taken > 0 {
+			log.Printf("User %s keeps email %q, %q belongs to another account", user.UserID, user.Email, email)
+			continue
+		}
+		if _, err := collection.UpdateByID(ctx, user.ID, bson.M{"$set": bson.M{"email": email}}); err != nil {
+			return verified, normalized, err
+		}
+		normalized++
+	}
+	return verified, normalized, cursor.Err()
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoOneTimeTokenStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoOneTimeTokenStore) Create(ctx context.Context, token *models.OneTimeToken) error {
+	res, err := s.collection.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		token.ID = id
+	}
+	return nil
+}
+
+func (s *mongoOneTimeTokenStore) Consume(ctx context.Context, tokenHash, purpose string) (*models.OneTimeToken, error) {
+	now := time.Now()
+	var token models.OneTimeToken
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"tokenHash": tokenHash,
+			"purpose":   purpose,
+			"usedAt":    bson.M{"$exists": false},
+			"expiresAt": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"usedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *mongoOneTimeTokenStore) DeleteByUser(ctx context.Context, userID, purpose string) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"userID": userID, "purpose": purpose})
+	return err
+}
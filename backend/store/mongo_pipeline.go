@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// pagedPipeline appends the filter, ordering and paging stages applied to
+// joined property documents.
+func pagedPipeline(pipeline mongo.Pipeline, filter bson.M, opts FindOptions) mongo.Pipeline {
+	if len(filter) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sortDocument(opts)}})
+	if opts.Skip > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: opts.Skip}})
+	}
+	if opts.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: opts.Limit}})
+	}
+	if len(opts.Fields) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: projectionDocument(opts.Fields)}})
+	}
+	return pipeline
+}
+
+func projectionDocument(fields []string) bson.M {
+	projection := bson.M{"_id": 1}
+	for _, field := range fields {
+		projection[field] = 1
+	}
+	return projection
+}
+
+func sortDocument(opts FindOptions) bson.D {
+	var sortDoc bson.D
+	for _, sf := range opts.sortWithTiebreaker() {
+		if sf.Field == TextScoreField {
+			if opts.TextScore {
+				sortDoc = append(sortDoc, bson.E{Key: sf.Field, Value: bson.M{"$meta": "textScore"}})
+			}
+			continue
+		}
+		direction := 1
+		if sf.Descending {
+			direction = -1
+		}
+		sortDoc = append(sortDoc, bson.E{Key: sf.Field, Value: direction})
+	}
+	return sortDoc
+}
+
+func countPipeline(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, filter bson.M) (int64, error) {
+	if len(filter) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$count", Value: "total"}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Total, nil
+}
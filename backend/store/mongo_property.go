@@ -0,0 +1,197 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoPropertyStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoPropertyStore) Create(ctx context.Context, property *models.Property) error {
+	_, err := s.collection.InsertOne(ctx, property)
+	return err
+}
+
+func (s *mongoPropertyStore) Find(ctx context.Context, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	findOptions := options.Find().SetSort(sortDocument(opts))
+	if opts.Skip > 0 {
+		findOptions.SetSkip(opts.Skip)
+	}
+	if opts.Limit > 0 {
+		findOptions.SetLimit(opts.Limit)
+	}
+	if len(opts.Fields) > 0 || opts.TextScore {
+		projection := bson.M{}
+		if len(opts.Fields) > 0 {
+			projection = projectionDocument(opts.Fields)
+		}
+		if opts.TextScore {
+			projection[TextScoreField] = bson.M{"$meta": "textScore"}
+		}
+		findOptions.SetProjection(projection)
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if !opts.TextScore {
+		var properties []models.Property
+		if err := cursor.All(ctx, &properties); err != nil {
+			return nil, err
+		}
+		return properties, nil
+	}
+
+	// Property.Score is not stored, so the projected score is decoded
+	// alongside the property.
+	var scored []struct {
+		models.Property `bson:",inline"`
+		Score           float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &scored); err != nil {
+		return nil, err
+	}
+	properties := make([]models.Property, len(scored))
+	for i, result := range scored {
+		properties[i] = result.Property
+		properties[i].Score = result.Score
+	}
+	return properties, nil
+}
+
+func (s *mongoPropertyStore) Count(ctx context.Context, filter bson.M) (int64, error) {
+	return s.collection.CountDocuments(ctx, filter)
+}
+
+func (s *mongoPropertyStore) Facets(ctx context.Context, filter bson.M, req FacetRequest) (FacetResults, error) {
+	facetStages := bson.M{}
+	for _, field := range req.Terms {
+		stages := bson.A{
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+		if req.MaxTerms > 0 {
+			stages = append(stages, bson.M{"$limit": req.MaxTerms})
+		}
+		facetStages[field] = stages
+	}
+	for field, boundaries := range req.Ranges {
+		// Only numbers compare with $gte, so missing, null and below range
+		// values are left out instead of landing in the default bucket.
+		facetStages[field] = bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$gte": boundaries[0]}}},
+			bson.M{"$bucket": bson.M{
+				"groupBy":    "$" + field,
+				"boundaries": boundaries,
+				"default":    "overflow",
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}},
+		}
+	}
+	if len(facetStages) == 0 {
+		return FacetResults{}, nil
+	}
+
+	pipeline := mongo.Pipeline{}
+	if len(filter) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: facetStages}})
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var raw []map[string][]struct {
+		ID    interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
+	}
+	if err := cursor.All(ctx, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return FacetResults{}, nil
+	}
+
+	results := FacetResults{}
+	for _, field := range req.Terms {
+		buckets := []FacetBucket{}
+		for _, group := range raw[0][field] {
+			buckets = append(buckets, FacetBucket{Value: group.ID, Count: group.Count})
+		}
+		results[field] = buckets
+	}
+	for field, boundaries := range req.Ranges {
+		buckets := rangeBuckets(boundaries)
+		for _, group := range raw[0][field] {
+			// $bucket labels each bucket with its lower boundary; values
+			// past the last boundary land in the default bucket.
+			i := len(buckets) - 1
+			if lower, ok := toFloat(group.ID); ok {
+				i = bucketIndex(boundaries, lower)
+			}
+			if i >= 0 {
+				buckets[i].Count += group.Count
+			}
+		}
+		results[field] = buckets
+	}
+	return results, nil
+}
+
+func (s *mongoPropertyStore) Update(ctx context.Context, id primitive.ObjectID, owner Owner, fields bson.M, verification *models.VerificationEvent) (bool, error) {
+	set := bson.M{}
+	for key, value := range fields {
+		set[key] = value
+	}
+	update := bson.M{"$set": set}
+	if verification != nil {
+		set["verificationStatus"] = verification.Status
+		set["isVerified"] = verification.Status == models.VerificationApproved
+		update["$push"] = bson.M{"verificationHistory": verification}
+	}
+
+	res, err := s.collection.UpdateOne(ctx, owner.filter(id), update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (s *mongoPropertyStore) Delete(ctx context.Context, id primitive.ObjectID, owner Owner) (bool, error) {
+	res, err := s.collection.DeleteOne(ctx, owner.filter(id))
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (s *mongoPropertyStore) SetVerification(ctx context.Context, id primitive.ObjectID, event models.VerificationEvent, unlessStatus string) (bool, error) {
+	filter := bson.M{"_id": id}
+	if unlessStatus != "" {
+		filter["verificationStatus"] = bson.M{"$ne": unlessStatus}
+	}
+	res, err := s.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"verificationStatus": event.Status,
+			"isVerified":         event.Status == models.VerificationApproved,
+		},
+		"$push": bson.M{"verificationHistory": event},
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
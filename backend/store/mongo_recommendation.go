@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRecommendationStore struct {
+	collection *mongo.Collection
+	properties *mongo.Collection
+}
+
+type recommendedProperty struct {
+	models.Property `bson:",inline"`
+	RecommendedBy   string `bson:"recommendedBy"`
+}
+
+func (s *mongoRecommendationStore) Create(ctx context.Context, recommendation *models.Recommendation) error {
+	_, err := s.collection.InsertOne(ctx, recommendation)
+	return err
+}
+
+func (s *mongoRecommendationStore) propertiesPipeline(toUserID string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"toUserID": toUserID}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         s.properties.Name(),
+			"localField":   "propertyID",
+			"foreignField": "_id",
+			"as":           "propertyDetails",
+		}}},
+		{{Key: "$unwind", Value: "$propertyDetails"}},
+		{{Key: "$replaceWith", Value: bson.M{
+			"$mergeObjects": bson.A{
+				"$propertyDetails",
+				bson.M{
+					"recommendedBy": "$fromUserId",
+				},
+			},
+		}}},
+	}
+}
+
+func (s *mongoRecommendationStore) ListProperties(ctx context.Context, toUserID string, filter bson.M, opts FindOptions) ([]models.Property, error) {
+	if len(opts.Fields) > 0 {
+		opts.Fields = append(append([]string{}, opts.Fields...), "recommendedBy")
+	}
+	cursor, err := s.collection.Aggregate(ctx, pagedPipeline(s.propertiesPipeline(toUserID), filter, opts))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []recommendedProperty
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	properties := make([]models.Property, 0, len(results))
+	for _, result := range results {
+		property := result.Property
+		property.RecommendedBy = result.RecommendedBy
+		properties = append(properties, property)
+	}
+	return properties, nil
+}
+
+func (s *mongoRecommendationStore) CountProperties(ctx context.Context, toUserID string, filter bson.M) (int64, error) {
+	return countPipeline(ctx, s.collection, s.propertiesPipeline(toUserID), filter)
+}
+
+func (s *mongoRecommendationStore) DeleteByProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"propertyID": propertyID})
+	return err
+}
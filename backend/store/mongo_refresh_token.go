@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRefreshTokenStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoRefreshTokenStore) Create(ctx context.Context, token *models.RefreshToken) error {
+	res, err := s.collection.InsertOne(ctx, token)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		token.ID = id
+	}
+	return nil
+}
+
+func (s *mongoRefreshTokenStore) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := s.collection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *mongoRefreshTokenStore) MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	res, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "usedAt": bson.M{"$exists": false}, "revoked": false},
+		bson.M{"$set": bson.M{"usedAt": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (s *mongoRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := s.collection.UpdateMany(ctx, bson.M{"familyID": familyID}, bson.M{"$set": bson.M{"revoked": true}})
+	return err
+}
+
+func (s *mongoRefreshTokenStore) RevokeUser(ctx context.Context, userID string) error {
+	_, err := s.collection.UpdateMany(ctx, bson.M{"userID": userID}, bson.M{"$set": bson.M{"revoked": true}})
+	return err
+}
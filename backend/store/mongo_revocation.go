@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoRevocationStore keeps revoked jtis in collection and the token version
+// of each user on the user document.
+type mongoRevocationStore struct {
+	collection *mongo.Collection
+	users      *mongo.Collection
+}
+
+func (s *mongoRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": jti},
+		bson.M{"$max": bson.M{"expiresAt": expiresAt}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *mongoRevocationStore) RevokeUserTokens(ctx context.Context, userID string) error {
+	result, err := s.users.UpdateOne(ctx, bson.M{"userID": userID}, bson.M{"$inc": bson.M{"tokenVersion": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *mongoRevocationStore) IsRevoked(ctx context.Context, jti, userID string, tokenVersion int) (bool, error) {
+	if jti != "" {
+		// The TTL monitor only runs periodically, so expired entries may linger.
+		n, err := s.collection.CountDocuments(ctx, bson.M{"_id": jti, "expiresAt": bson.M{"$gt": time.Now()}})
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+
+	var user struct {
+		TokenVersion int `bson:"tokenVersion"`
+	}
+	err := s.users.FindOne(ctx, bson.M{"userID": userID}, options.FindOne().SetProjection(bson.M{"tokenVersion": 1})).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tokenVersion < user.TokenVersion, nil
+}
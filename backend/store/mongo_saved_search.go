@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSavedSearchStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoSavedSearchStore) Create(ctx context.Context, search *models.SavedSearch) error {
+	res, err := s.collection.InsertOne(ctx, search)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		search.ID = id
+	}
+	return nil
+}
+
+func (s *mongoSavedSearchStore) ListByUser(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{"userID": userID}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	searches := []models.SavedSearch{}
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (s *mongoSavedSearchStore) ForEach(ctx context.Context, fn func(models.SavedSearch) error) error {
+	cursor, err := s.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var search models.SavedSearch
+		if err := cursor.Decode(&search); err != nil {
+			return err
+		}
+		if err := fn(search); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (s *mongoSavedSearchStore) Delete(ctx context.Context, id primitive.ObjectID, userID string) (bool, error) {
+	res, err := s.collection.DeleteOne(ctx, bson.M{"_id": id, "userID": userID})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+type mongoNotificationStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoNotificationStore) Create(ctx context.Context, notification *models.Notification) (bool, error) {
+	res, err := s.collection.InsertOne(ctx, notification)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		notification.ID = id
+	}
+	return true, nil
+}
+
+func (s *mongoNotificationStore) ListByUser(ctx context.Context, userID string, limit int64) ([]models.Notification, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := s.collection.Find(ctx, bson.M{"userID": userID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (s *mongoNotificationStore) DeleteBySavedSearch(ctx context.Context, savedSearchID primitive.ObjectID) error {
+	_, err := s.collection.DeleteMany(ctx, bson.M{"savedSearchID": savedSearchID})
+	return err
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoUserStore struct {
+	collection *mongo.Collection
+}
+
+func (s *mongoUserStore) Create(ctx context.Context, user *models.User) error {
+	_, err := s.collection.InsertOne(ctx, user)
+	return err
+}
+
+func (s *mongoUserStore) FindByUserID(ctx context.Context, userID string) (*models.User, error) {
+	return s.findOne(ctx, bson.M{"userID": userID})
+}
+
+func (s *mongoUserStore) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.findOne(ctx, bson.M{"email": models.NormalizeEmail(email)})
+}
+
+func (s *mongoUserStore) SetRole(ctx context.Context, userID, role string) (bool, error) {
+	res, err := s.collection.UpdateOne(ctx, bson.M{"userID": userID}, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (s *mongoUserStore) SetPassword(ctx context.Context, userID, hashedPassword string) (bool, error) {
+	res, err := s.collection.UpdateOne(ctx, bson.M{"userID": userID}, bson.M{"$set": bson.M{"password": hashedPassword}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (s *mongoUserStore) SetVerified(ctx context.Context, userID string) (bool, error) {
+	res, err := s.collection.UpdateOne(ctx, bson.M{"userID": userID}, bson.M{"$set": bson.M{"verified": true}})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+func (s *mongoUserStore) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var user models.User
+	err := s.collection.FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,191 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dcode-github/property_lisitng_system/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrNotFound = errors.New("store: document not found")
+
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// FindOptions pages through results ordered by Sort. Implementations always
+// add an ascending _id tiebreaker so the order is deterministic. When Fields
+// is set only those fields (and _id) are loaded; backends may return more.
+// TextScore loads the relevance of a $text filter into Property.Score and
+// allows sorting on TextScoreField.
+type FindOptions struct {
+	Limit     int64
+	Skip      int64
+	Sort      []SortField
+	Fields    []string
+	TextScore bool
+}
+
+func (o FindOptions) sortWithTiebreaker() []SortField {
+	for _, sf := range o.Sort {
+		if sf.Field == "_id" {
+			return o.Sort
+		}
+	}
+	return append(append([]SortField{}, o.Sort...), SortField{Field: "_id"})
+}
+
+// FacetRequest asks for value counts on Terms fields and for counts of
+// documents falling into [Ranges[f][i], Ranges[f][i+1]) buckets. Values past
+// the last boundary are counted in an open-ended bucket.
+type FacetRequest struct {
+	Terms    []string
+	Ranges   map[string][]float64
+	MaxTerms int
+}
+
+type FacetBucket struct {
+	Value interface{} `json:"value,omitempty"`
+	Min   *float64    `json:"min,omitempty"`
+	Max   *float64    `json:"max,omitempty"`
+	Count int64       `json:"count"`
+}
+
+type FacetResults map[string][]FacetBucket
+
+// Owner restricts Update and Delete to the properties of one user, or lets
+// them match any property.
+type Owner struct {
+	userID string
+	any    bool
+}
+
+func OwnedBy(userID string) Owner {
+	return Owner{userID: userID}
+}
+
+var AnyOwner = Owner{any: true}
+
+func (o Owner) matches(createdBy string) bool {
+	return o.any || createdBy == o.userID
+}
+
+func (o Owner) filter(id primitive.ObjectID) bson.M {
+	if o.any {
+		return bson.M{"_id": id}
+	}
+	return bson.M{"_id": id, "createdBy": o.userID}
+}
+
+type PropertyStore interface {
+	Create(ctx context.Context, property *models.Property) error
+	Find(ctx context.Context, filter bson.M, opts FindOptions) ([]models.Property, error)
+	Count(ctx context.Context, filter bson.M) (int64, error)
+	Facets(ctx context.Context, filter bson.M, req FacetRequest) (FacetResults, error)
+	// Update sets fields on the listing. A non-nil verification event is
+	// applied in the same write, as SetVerification would without
+	// unlessStatus.
+	Update(ctx context.Context, id primitive.ObjectID, owner Owner, fields bson.M, verification *models.VerificationEvent) (bool, error)
+	Delete(ctx context.Context, id primitive.ObjectID, owner Owner) (bool, error)
+	// SetVerification makes event.Status the listing's verification status
+	// and appends event to its history. When unlessStatus is set, listings
+	// already in that status are left alone. It reports whether the listing
+	// was changed.
+	SetVerification(ctx context.Context, id primitive.ObjectID, event models.VerificationEvent, unlessStatus string) (bool, error)
+}
+
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByUserID(ctx context.Context, userID string) (*models.User, error)
+	// FindByEmail matches emails in their models.NormalizeEmail form.
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	SetRole(ctx context.Context, userID, role string) (bool, error)
+	SetPassword(ctx context.Context, userID, hashedPassword string) (bool, error)
+	SetVerified(ctx context.Context, userID string) (bool, error)
+}
+
+type FavoriteStore interface {
+	Add(ctx context.Context, favorite *models.Favorite) error
+	Exists(ctx context.Context, userID string, propertyID primitive.ObjectID) (bool, error)
+	Remove(ctx context.Context, userID string, propertyID primitive.ObjectID) (bool, error)
+	ListPropertyIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error)
+	// ListProperties and CountProperties apply filter to the joined property
+	// documents.
+	ListProperties(ctx context.Context, userID string, filter bson.M, opts FindOptions) ([]models.Property, error)
+	CountProperties(ctx context.Context, userID string, filter bson.M) (int64, error)
+	DeleteByProperty(ctx context.Context, propertyID primitive.ObjectID) error
+}
+
+type RecommendationStore interface {
+	Create(ctx context.Context, recommendation *models.Recommendation) error
+	ListProperties(ctx context.Context, toUserID string, filter bson.M, opts FindOptions) ([]models.Property, error)
+	CountProperties(ctx context.Context, toUserID string, filter bson.M) (int64, error)
+	DeleteByProperty(ctx context.Context, propertyID primitive.ObjectID) error
+}
+
+type SavedSearchStore interface {
+	Create(ctx context.Context, search *models.SavedSearch) error
+	ListByUser(ctx context.Context, userID string) ([]models.SavedSearch, error)
+	// ForEach calls fn for every saved search of every user, stopping at the
+	// first error.
+	ForEach(ctx context.Context, fn func(models.SavedSearch) error) error
+	Delete(ctx context.Context, id primitive.ObjectID, userID string) (bool, error)
+}
+
+type NotificationStore interface {
+	// Create records a notification unless one already exists for the same
+	// saved search and property. It reports whether one was recorded.
+	Create(ctx context.Context, notification *models.Notification) (bool, error)
+	// ListByUser returns the user's newest notifications first.
+	ListByUser(ctx context.Context, userID string, limit int64) ([]models.Notification, error)
+	DeleteBySavedSearch(ctx context.Context, savedSearchID primitive.ObjectID) error
+}
+
+type RefreshTokenStore interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// MarkUsed records that the token was exchanged. It reports false when
+	// the token was already used or revoked, so only one exchange can win.
+	MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUser(ctx context.Context, userID string) error
+}
+
+// RevocationStore rejects access tokens before they expire. Single tokens are
+// revoked by jti, and all tokens of a user by raising the user's token
+// version.
+type RevocationStore interface {
+	// RevokeToken rejects the token with the given jti until expiresAt.
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeUserTokens increments the token version of userID, rejecting
+	// every token issued with an older version.
+	RevokeUserTokens(ctx context.Context, userID string) error
+	// IsRevoked reports whether the token with the given jti was revoked or
+	// carries an outdated token version. Tokens of unknown users are revoked.
+	IsRevoked(ctx context.Context, jti, userID string, tokenVersion int) (bool, error)
+}
+
+type OneTimeTokenStore interface {
+	Create(ctx context.Context, token *models.OneTimeToken) error
+	// Consume marks the unexpired, unused token with the given hash and
+	// purpose as used and returns it, or ErrNotFound.
+	Consume(ctx context.Context, tokenHash, purpose string) (*models.OneTimeToken, error)
+	// DeleteByUser drops the user's tokens for purpose, used or not.
+	DeleteByUser(ctx context.Context, userID, purpose string) error
+}
+
+type Store struct {
+	Properties      PropertyStore
+	Users           UserStore
+	Favorites       FavoriteStore
+	Recommendations RecommendationStore
+	SavedSearches   SavedSearchStore
+	Notifications   NotificationStore
+	RefreshTokens   RefreshTokenStore
+	Revocations     RevocationStore
+	OneTimeTokens   OneTimeTokenStore
+}
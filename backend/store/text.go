@@ -0,0 +1,93 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TextScoreField holds the relevance of a property to a $text search when
+// FindOptions.TextScore is set. Sorting on it is always by descending score.
+const TextScoreField = "score"
+
+// propertyTextWeights are the fields covered by the properties text index and
+// how much a match in each contributes to the score.
+var propertyTextWeights = map[string]int32{
+	"title": 10, "tags": 5, "amenities": 5, "city": 3, "state": 1,
+}
+
+// textSearch returns the terms of the $text search in filter, looking
+// through top-level $and clauses the way Mongo requires it to be placed.
+func textSearch(filter bson.M) ([]string, bool) {
+	if cond, ok := filter["$text"]; ok {
+		terms, err := textSearchTerms(cond)
+		return terms, err == nil
+	}
+	if and, ok := filter["$and"]; ok {
+		for _, item := range toList(and) {
+			if f, ok := toFilter(item); ok {
+				if terms, ok := textSearch(f); ok {
+					return terms, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+func textSearchTerms(cond interface{}) ([]string, error) {
+	spec, ok := toFilter(cond)
+	if !ok {
+		return nil, fmt.Errorf("$text expects a document, got %T", cond)
+	}
+	search, ok := spec["$search"].(string)
+	if !ok {
+		return nil, fmt.Errorf("$text requires a $search string")
+	}
+	return tokenize(search), nil
+}
+
+// matchText reports whether any search term appears in an indexed field.
+func matchText(doc bson.M, cond interface{}) (bool, error) {
+	terms, err := textSearchTerms(cond)
+	if err != nil {
+		return false, err
+	}
+	return textScore(doc, terms) > 0, nil
+}
+
+// textScore approximates Mongo's relevance: weighted term frequency without
+// stemming.
+func textScore(doc bson.M, terms []string) float64 {
+	var score float64
+	for field, weight := range propertyTextWeights {
+		var tokens []string
+		for _, v := range toList(lookupField(doc, field)) {
+			if s, ok := v.(string); ok {
+				tokens = append(tokens, tokenize(s)...)
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		hits := 0
+		for _, token := range tokens {
+			for _, term := range terms {
+				if token == term {
+					hits++
+				}
+			}
+		}
+		score += float64(weight) * float64(hits) / float64(len(tokens))
+	}
+	return score
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -8,19 +8,50 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
+// Claims identify the user, their role and the login session (refresh token
+// family) a token belongs to. StandardClaims.Id is the token's unique jti and
+// TokenVersion the user's token version at the time it was issued.
 type Claims struct {
-	UserID string `json:"userID"`
+	UserID       string `json:"userID"`
+	Role         string `json:"role,omitempty"`
+	SessionID    string `json:"sid,omitempty"`
+	TokenVersion int    `json:"ver,omitempty"`
 	jwt.StandardClaims
 }
 
 var jwtKey = []byte(os.Getenv("JWT_KEY"))
 
-func GenerateJWT(userID string) (string, error) {
-	expirationTime := time.Now().Add(15 * time.Minute)
+// TokenLifetimes sets how long access tokens (JWTs), refresh tokens and the
+// mailed password reset and email verification tokens are valid.
+type TokenLifetimes struct {
+	Access            time.Duration
+	Refresh           time.Duration
+	PasswordReset     time.Duration
+	EmailVerification time.Duration
+}
+
+var DefaultTokenLifetimes = TokenLifetimes{
+	Access:            15 * time.Minute,
+	Refresh:           30 * 24 * time.Hour,
+	PasswordReset:     time.Hour,
+	EmailVerification: 48 * time.Hour,
+}
+
+func GenerateJWT(userID, role, sessionID string, tokenVersion int, ttl time.Duration) (string, error) {
+	expirationTime := time.Now().Add(ttl)
+
+	jti, _, err := NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
 
 	claims := &Claims{
-		UserID: userID,
+		UserID:       userID,
+		Role:         role,
+		SessionID:    sessionID,
+		TokenVersion: tokenVersion,
 		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
 			ExpiresAt: expirationTime.Unix(),
 			IssuedAt:  time.Now().Unix(),
 			Issuer:    "property_listing_system",
@@ -0,0 +1,220 @@
+// Package validation checks models against rules declared in `validate`
+// struct tags. Fields are reported by their JSON name.
+//
+// Supported rules, separated by commas:
+//
+//	required     the value must not be zero (or blank for strings)
+//	min=N        numbers must be >= N, strings and lists at least N long
+//	max=N        numbers must be <= N, strings and lists at most N long
+//	oneof=a|b|c  a non-empty string must be one of the listed values
+//	email        a non-empty string must be a bare address like a@example.com
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Errors collects every problem found in a document.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Reason
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *Errors) Add(field, reason string) {
+	*e = append(*e, FieldError{Field: field, Reason: reason})
+}
+
+// Err returns e as an error, or nil when nothing was found.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Struct checks every field of the struct v points to.
+func Struct(v interface{}) Errors {
+	return check(v, nil)
+}
+
+// Fields checks only the named fields, for partial updates.
+func Fields(v interface{}, names []string) Errors {
+	only := make(map[string]bool, len(names))
+	for _, name := range names {
+		only[name] = true
+	}
+	return check(v, only)
+}
+
+// DecodeFields decodes each raw value into the field of v with the same JSON
+// name, reporting unknown fields and values of the wrong type per field.
+func DecodeFields(raw map[string]json.RawMessage, v interface{}) Errors {
+	rv := reflect.ValueOf(v).Elem()
+	byName := make(map[string]reflect.Value)
+	for i := 0; i < rv.NumField(); i++ {
+		if name := jsonName(rv.Type().Field(i)); name != "" {
+			byName[name] = rv.Field(i)
+		}
+	}
+
+	var errs Errors
+	for _, name := range slices.Sorted(maps.Keys(raw)) {
+		field, ok := byName[name]
+		if !ok {
+			errs.Add(name, "unknown field")
+			continue
+		}
+		if err := json.Unmarshal(raw[name], field.Addr().Interface()); err != nil {
+			errs.Add(name, "invalid value: "+typeDescription(field.Type()))
+		}
+	}
+	return errs
+}
+
+func check(v interface{}, only map[string]bool) Errors {
+	rv := reflect.ValueOf(v).Elem()
+	var errs Errors
+	for i := 0; i < rv.NumField(); i++ {
+		sf := rv.Type().Field(i)
+		rules, ok := sf.Tag.Lookup("validate")
+		name := jsonName(sf)
+		if !ok || name == "" || only != nil && !only[name] {
+			continue
+		}
+		if reason := checkRules(rv.Field(i), rules); reason != "" {
+			errs.Add(name, reason)
+		}
+	}
+	return errs
+}
+
+// checkRules returns the reason the first failing rule gives, or "".
+func checkRules(value reflect.Value, rules string) string {
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if isBlank(value) {
+				return "is required"
+			}
+		case "min", "max":
+			if reason := checkBound(value, name, arg); reason != "" {
+				return reason
+			}
+		case "oneof":
+			allowed := strings.Split(arg, "|")
+			if s := value.String(); s != "" && !slices.Contains(allowed, s) {
+				return "must be one of " + strings.Join(allowed, ", ")
+			}
+		case "email":
+			if s := value.String(); s != "" && !isEmailAddress(s) {
+				return "must be a valid email address"
+			}
+		default:
+			panic(fmt.Sprintf("validation: unknown rule %q", rule))
+		}
+	}
+	return ""
+}
+
+func checkBound(value reflect.Value, rule, arg string) string {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid %s bound %q", rule, arg))
+	}
+
+	var (
+		actual float64
+		unit   string
+	)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	case reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice:
+		actual, unit = float64(value.Len()), " items"
+	default:
+		panic(fmt.Sprintf("validation: %s does not apply to %s", rule, value.Type()))
+	}
+
+	switch {
+	case rule == "min" && actual < bound:
+		if unit == "" {
+			return "must be at least " + arg
+		}
+		return "must have at least " + arg + unit
+	case rule == "max" && actual > bound:
+		if unit == "" {
+			return "must be at most " + arg
+		}
+		return "must have at most " + arg + unit
+	}
+	return ""
+}
+
+// isEmailAddress accepts RFC 5322 addresses without a display name or angle
+// brackets, and requires a dot in the domain.
+func isEmailAddress(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return false
+	}
+	_, domain, _ := strings.Cut(s, "@")
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+func isBlank(value reflect.Value) bool {
+	if value.Kind() == reflect.String {
+		return strings.TrimSpace(value.String()) == ""
+	}
+	return value.IsZero()
+}
+
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "-" || !sf.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+func typeDescription(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "expected an integer"
+	case reflect.Float32, reflect.Float64:
+		return "expected a number"
+	case reflect.Bool:
+		return "expected true or false"
+	case reflect.String:
+		return "expected a string"
+	}
+	if t.String() == "time.Time" {
+		return "expected an RFC 3339 timestamp"
+	}
+	return "expected " + t.String()
+}
@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type listing struct {
+	Title    string    `json:"title" validate:"required,max=5"`
+	Kind     string    `json:"kind" validate:"oneof=flat|house"`
+	Contact  string    `json:"contact" validate:"email"`
+	Price    int       `json:"price" validate:"min=1"`
+	Rating   float64   `json:"rating" validate:"min=0,max=5"`
+	Tags     []string  `json:"tags" validate:"min=1,max=2"`
+	Listed   time.Time `json:"listed" validate:"required"`
+	Verified bool      `json:"verified"`
+	Owner    string    `json:"-" validate:"required"`
+	Untagged string    `validate:"max=3"`
+}
+
+func validListing() listing {
+	return listing{
+		Title:  "Flat",
+		Kind:   "flat",
+		Price:  100,
+		Rating: 4.5,
+		Tags:   []string{"gym"},
+		Listed: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(l *listing)
+		want   Errors
+	}{
+		{name: "valid", modify: func(l *listing) {}},
+		{name: "required string", modify: func(l *listing) { l.Title = "" },
+			want: Errors{{Field: "title", Reason: "is required"}}},
+		{name: "blank string is missing", modify: func(l *listing) { l.Title = "   " },
+			want: Errors{{Field: "title", Reason: "is required"}}},
+		{name: "required time", modify: func(l *listing) { l.Listed = time.Time{} },
+			want: Errors{{Field: "listed", Reason: "is required"}}},
+		{name: "max counts characters, not bytes", modify: func(l *listing) { l.Title = "Wohnü" }},
+		{name: "string too long", modify: func(l *listing) { l.Title = "Bungalow" },
+			want: Errors{{Field: "title", Reason: "must have at most 5 characters"}}},
+		{name: "oneof", modify: func(l *listing) { l.Kind = "castle" },
+			want: Errors{{Field: "kind", Reason: "must be one of flat, house"}}},
+		{name: "oneof allows empty", modify: func(l *listing) { l.Kind = "" }},
+		{name: "email", modify: func(l *listing) { l.Contact = "owner@example.com" }},
+		{name: "email allows empty", modify: func(l *listing) { l.Contact = "" }},
+		{name: "email without domain dot", modify: func(l *listing) { l.Contact = "owner@localhost" },
+			want: Errors{{Field: "contact", Reason: "must be a valid email address"}}},
+		{name: "email with display name", modify: func(l *listing) { l.Contact = "Owner <owner@example.com>" },
+			want: Errors{{Field: "contact", Reason: "must be a valid email address"}}},
+		{name: "not an email", modify: func(l *listing) { l.Contact = "owner" },
+			want: Errors{{Field: "contact", Reason: "must be a valid email address"}}},
+		{name: "number below min", modify: func(l *listing) { l.Price = 0 },
+			want: Errors{{Field: "price", Reason: "must be at least 1"}}},
+		{name: "float above max", modify: func(l *listing) { l.Rating = 5.5 },
+			want: Errors{{Field: "rating", Reason: "must be at most 5"}}},
+		{name: "float bounds are inclusive", modify: func(l *listing) { l.Rating = 5 }},
+		{name: "list too short", modify: func(l *listing) { l.Tags = nil },
+			want: Errors{{Field: "tags", Reason: "must have at least 1 items"}}},
+		{name: "list too long", modify: func(l *listing) { l.Tags = []string{"a", "b", "c"} },
+			want: Errors{{Field: "tags", Reason: "must have at most 2 items"}}},
+		{name: "json name defaults to the field name", modify: func(l *listing) { l.Untagged = "long" },
+			want: Errors{{Field: "Untagged", Reason: "must have at most 3 characters"}}},
+		{name: "fields hidden from json are skipped", modify: func(l *listing) { l.Owner = "" }},
+		{name: "every field is reported in order", modify: func(l *listing) { l.Title, l.Price = "", -1 },
+			want: Errors{
+				{Field: "title", Reason: "is required"},
+				{Field: "price", Reason: "must be at least 1"},
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := validListing()
+			tt.modify(&l)
+			if got := Struct(&l); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	l := listing{Price: 0, Title: ""}
+	want := Errors{{Field: "price", Reason: "must be at least 1"}}
+	if got := Fields(&l, []string{"price", "rating"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    listing
+		wantErr Errors
+	}{
+		{name: "decodes each field", body: `{"title":"Flat","price":5,"rating":2.5,"tags":["a"],"verified":true,"listed":"2024-03-01T00:00:00Z"}`,
+			want: listing{Title: "Flat", Price: 5, Rating: 2.5, Tags: []string{"a"}, Verified: true,
+				Listed: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+		{name: "empty body", body: `{}`},
+		{name: "field names without json tag", body: `{"Untagged":"x"}`, want: listing{Untagged: "x"}},
+		{name: "unknown field", body: `{"colour":"red"}`,
+			wantErr: Errors{{Field: "colour", Reason: "unknown field"}}},
+		{name: "fields hidden from json are unknown", body: `{"Owner":"me"}`,
+			wantErr: Errors{{Field: "Owner", Reason: "unknown field"}}},
+		{name: "wrong types are reported per field", body: `{"verified":"yes","title":1,"price":"1","rating":"x","listed":"soon","tags":"a"}`,
+			wantErr: Errors{
+				{Field: "listed", Reason: "invalid value: expected an RFC 3339 timestamp"},
+				{Field: "price", Reason: "invalid value: expected an integer"},
+				{Field: "rating", Reason: "invalid value: expected a number"},
+				{Field: "tags", Reason: "invalid value: expected []string"},
+				{Field: "title", Reason: "invalid value: expected a string"},
+				{Field: "verified", Reason: "invalid value: expected true or false"},
+			}},
+		{name: "fractional integer", body: `{"price":1.5}`,
+			wantErr: Errors{{Field: "price", Reason: "invalid value: expected an integer"}}},
+		{name: "valid fields are kept next to errors", body: `{"title":"Flat","colour":"red"}`,
+			want:    listing{Title: "Flat"},
+			wantErr: Errors{{Field: "colour", Reason: "unknown field"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.body), &raw); err != nil {
+				t.Fatal(err)
+			}
+
+			var got listing
+			errs := DecodeFields(raw, &got)
+			if !reflect.DeepEqual(errs, tt.wantErr) {
+				t.Errorf("DecodeFields() errors = %v, want %v", errs, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeFields() decoded %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	if errs.Err() != nil {
+		t.Fatalf("Err() on no errors = %v, want nil", errs.Err())
+	}
+	errs.Add("title", "is required")
+	errs.Add("price", "must be at least 1")
+	if got, want := errs.Err().Error(), "title: is required; price: must be at least 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}